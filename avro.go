@@ -0,0 +1,73 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maxAvroDocuments is the number of documents kept by ParseAvro, the least
+// recently used ones are evicted past it.
+const maxAvroDocuments = 1000
+
+// avroDocuments caches the documents parsed by ParseAvro by schema string, out
+// of the Schema so its value is left unchanged.
+var avroDocuments = newLRUCache(maxAvroDocuments)
+
+// ParseAvro decodes the Avro schema string into its JSON document. The
+// primitive and union schemas (e.g. `"string"` or `["null", "string"]`) are
+// returned in their equivalent form `{"type": ...}`, so the document is always
+// a JSON object.
+//
+// The documents of the last 1000 schema strings parsed are cached, so the hot
+// paths don't parse again the same schema. Each call returns a copy of the
+// cached document, owned by the caller.
+func (s *Schema) ParseAvro() (map[string]interface{}, error) {
+	if s.SchemaType != "" && s.SchemaType != SchemaTypeAvro {
+		return nil, fmt.Errorf("failed to parse the Avro schema: the schema type is %s", s.SchemaType)
+	}
+
+	if avro, ok := avroDocuments.get(s.Schema); ok {
+		return copyAvroDocument(avro).(map[string]interface{}), nil
+	}
+
+	var document interface{}
+	err := json.Unmarshal([]byte(s.Schema), &document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Avro schema: %s", err)
+	}
+
+	var avro map[string]interface{}
+	switch document := document.(type) {
+	case map[string]interface{}:
+		avro = document
+	case string, []interface{}:
+		avro = map[string]interface{}{"type": document}
+	default:
+		return nil, fmt.Errorf("failed to parse the Avro schema: unexpected %s", s.Schema)
+	}
+
+	avroDocuments.store(s.Schema, copyAvroDocument(avro))
+
+	return avro, nil
+}
+
+// copyAvroDocument returns a deep copy of the JSON value, for the callers to
+// own the documents returned by ParseAvro.
+func copyAvroDocument(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			copied[k] = copyAvroDocument(v)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, v := range value {
+			copied[i] = copyAvroDocument(v)
+		}
+		return copied
+	default:
+		return value
+	}
+}
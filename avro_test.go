@@ -0,0 +1,79 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Schema_ParseAvro_record(t *testing.T) {
+	schema := &Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}]}`}
+
+	avro, err := schema.ParseAvro()
+
+	require.NoError(t, err)
+	assert.Equal(t, "record", avro["type"])
+	assert.Equal(t, "user", avro["name"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "id", "type": "long"},
+	}, avro["fields"])
+}
+
+func Test_Schema_ParseAvro_primitive_and_union(t *testing.T) {
+	avro, err := (&Schema{Schema: `"string"`}).ParseAvro()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "string"}, avro)
+
+	avro, err = (&Schema{Schema: `["null", "string"]`}).ParseAvro()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": []interface{}{"null", "string"}}, avro)
+}
+
+func Test_Schema_ParseAvro_caches_the_document(t *testing.T) {
+	schema := &Schema{Schema: `{"type": "record", "name": "cached", "fields": []}`}
+
+	_, err := schema.ParseAvro()
+	require.NoError(t, err)
+
+	cached, ok := avroDocuments.get(schema.Schema)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"type": "record", "name": "cached", "fields": []interface{}{}}, cached)
+
+	// A schema string in the cache is not parsed again, even if invalid.
+	avroDocuments.store("not parsed", map[string]interface{}{"type": "string"})
+
+	avro, err := (&Schema{Schema: "not parsed"}).ParseAvro()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"type": "string"}, avro)
+	assert.Equal(t, &Schema{Schema: `{"type": "record", "name": "cached", "fields": []}`}, schema)
+}
+
+func Test_Schema_ParseAvro_returns_a_copy(t *testing.T) {
+	schema := &Schema{Schema: `{"type": "record", "name": "copied", "fields": [{"name": "id", "type": "long"}]}`}
+
+	for i := 0; i < 2; i++ {
+		avro, err := schema.ParseAvro()
+		require.NoError(t, err)
+		assert.Equal(t, "copied", avro["name"])
+		assert.Equal(t, "id", avro["fields"].([]interface{})[0].(map[string]interface{})["name"])
+
+		avro["name"] = "modified"
+		avro["fields"].([]interface{})[0].(map[string]interface{})["name"] = "modified"
+	}
+}
+
+func Test_Schema_ParseAvro_invalid_schema(t *testing.T) {
+	_, err := (&Schema{Schema: `{"type": "record",`}).ParseAvro()
+	assert.EqualError(t, err, "failed to parse the Avro schema: unexpected end of JSON input")
+
+	_, err = (&Schema{Schema: `42`}).ParseAvro()
+	assert.EqualError(t, err, "failed to parse the Avro schema: unexpected 42")
+}
+
+func Test_Schema_ParseAvro_with_another_schema_type(t *testing.T) {
+	_, err := (&Schema{Schema: `syntax = "proto3";`, SchemaType: SchemaTypeProtobuf}).ParseAvro()
+
+	assert.EqualError(t, err, "failed to parse the Avro schema: the schema type is PROTOBUF")
+}
@@ -0,0 +1,193 @@
+package schemaregistry
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency is the default number of concurrent requests made by the
+// batch operations.
+const defaultConcurrency = 4
+
+// UsingConcurrency modifies the maximum number of concurrent requests made by
+// the batch operations like `SchemaCompatibilityByVersion`. It defaults to 4.
+func UsingConcurrency(n int) Option {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
+
+// forEach calls fn for each item with at most c.concurrency calls at the same
+// time, see `forEachConcurrently`.
+func (c *Client) forEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	return forEachConcurrently(ctx, c.concurrency, n, fn)
+}
+
+// forEachConcurrently calls fn for each item with at most concurrency calls at
+// the same time. It returns the first error returned by fn, the remaining calls
+// are skipped once an error occurred.
+func forEachConcurrently(ctx context.Context, concurrency int, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+
+	sem := make(chan struct{}, concurrency)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := fn(ctx, i)
+			if err != nil {
+				lock.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				lock.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
+
+// SchemaCompatibilityByVersion tests the schema against each version of the
+// subject and returns the compatibility per version number. Unlike a
+// transitive check, it tells exactly which versions are broken by a change.
+//
+// The checks are made concurrently, look `UsingConcurrency`.
+func (c *Client) SchemaCompatibilityByVersion(ctx context.Context, schema string, subject string) (map[int]bool, error) {
+	ctx = withOperation(ctx, "SchemaCompatibilityByVersion")
+
+	versions, err := c.Versions(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	compatibilities := make([]bool, len(versions))
+
+	err = c.forEach(ctx, len(versions), func(ctx context.Context, i int) error {
+		isCompatible, err := c.SchemaCompatibleWith(ctx, schema, subject, versions[i])
+		if err != nil {
+			return err
+		}
+
+		compatibilities[i] = isCompatible
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int]bool, len(versions))
+	for i, version := range versions {
+		res[version] = compatibilities[i]
+	}
+
+	return res, nil
+}
+
+// DeleteAllSchemaVersions soft-deletes every version of the subject one by one
+// and returns the deleted version numbers. Unlike `DeleteSubject`, the subject
+// configuration is kept.
+//
+// It stops at the first error, returning the versions deleted so far with it.
+// A failed deletion is returned in a MultiError telling which version failed.
+func (c *Client) DeleteAllSchemaVersions(ctx context.Context, subject string) ([]int, error) {
+	ctx = withOperation(ctx, "DeleteAllSchemaVersions")
+
+	versions, err := c.Versions(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []int{}
+	for _, version := range versions {
+		if ctx.Err() != nil {
+			return deleted, ctx.Err()
+		}
+
+		_, err = c.DeleteSchemaVersion(ctx, subject, version, false)
+		if err != nil {
+			return deleted, MultiError{SubjectVersion{Subject: subject, Version: version}: err}
+		}
+
+		deleted = append(deleted, version)
+	}
+
+	return deleted, nil
+}
+
+// GetConfigs returns the configuration of each subject. A subject without
+// configuration of its own gets an empty Config, meaning it uses the global
+// settings like with `GetConfig`.
+//
+// The configurations are fetched concurrently, look `UsingConcurrency`. The
+// failures don't stop the other fetches: the configurations fetched are
+// returned with a MultiError holding the failures, keyed by subject.
+func (c *Client) GetConfigs(ctx context.Context, subjects []string) (map[string]*Config, error) {
+	ctx = withOperation(ctx, "GetConfigs")
+
+	configs := make([]*Config, len(subjects))
+	errs := make([]error, len(subjects))
+
+	err := c.forEach(ctx, len(subjects), func(ctx context.Context, i int) error {
+		config, err := c.GetConfig(ctx, subjects[i])
+		if IsSubjectNotFound(err) || HasErrorCode(err, SubjectLevelCompatibilityNotConfigured) {
+			config, err = &Config{}, nil
+		}
+
+		configs[i], errs[i] = config, err
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*Config, len(subjects))
+	multiErr := MultiError{}
+
+	for i, subject := range subjects {
+		if errs[i] != nil {
+			multiErr[SubjectVersion{Subject: subject}] = errs[i]
+			continue
+		}
+
+		res[subject] = configs[i]
+	}
+
+	if len(multiErr) > 0 {
+		return res, multiErr
+	}
+
+	return res, nil
+}
@@ -0,0 +1,270 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SchemaCompatibilityByVersion_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.String() {
+		case "/subjects/test/versions":
+			body = `[1, 2, 3]`
+		case "/compatibility/subjects/test/versions/1":
+			body = `{"is_compatible": false}`
+		case "/compatibility/subjects/test/versions/2", "/compatibility/subjects/test/versions/3":
+			body = `{"is_compatible": true}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	compatibilities, err := client.SchemaCompatibilityByVersion(context.Background(), `{"type": "string"}`, "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, map[int]bool{1: false, 2: true, 3: true}, compatibilities)
+}
+
+func Test_SchemaCompatibilityByVersion_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.String() == "/subjects/test/versions" {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[1, 2]`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40402, "message": "version not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	compatibilities, err := client.SchemaCompatibilityByVersion(context.Background(), `{"type": "string"}`, "test")
+
+	assert.Nil(t, compatibilities)
+	assert.True(t, IsVersionNotFound(err))
+}
+
+func Test_SchemaCompatibilityByVersion_with_a_bounded_concurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var once sync.Once
+	concurrent := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.String() == "/subjects/test/versions" {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[1, 2, 3, 4, 5, 6, 7, 8]`))
+			require.NoError(t, err)
+			return
+		}
+
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		// The first checks are held until two of them run concurrently.
+		if current == 2 {
+			once.Do(func() { close(concurrent) })
+		}
+		<-concurrent
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": true}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConcurrency(2))
+	require.NoError(t, err)
+
+	compatibilities, err := client.SchemaCompatibilityByVersion(context.Background(), `{"type": "string"}`, "test")
+
+	assert.NoError(t, err)
+	assert.Len(t, compatibilities, 8)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&maxInFlight))
+}
+
+func Test_DeleteAllSchemaVersions_success(t *testing.T) {
+	var deleted []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[1, 2, 3]`))
+			require.NoError(t, err)
+			return
+		}
+
+		assert.Equal(t, "DELETE", r.Method)
+		deleted = append(deleted, r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`1`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteAllSchemaVersions(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2, 3}, versions)
+	assert.EqualValues(t, []string{
+		"/subjects/test/versions/1?permanent=false",
+		"/subjects/test/versions/2?permanent=false",
+		"/subjects/test/versions/3?permanent=false",
+	}, deleted)
+}
+
+func Test_DeleteAllSchemaVersions_with_a_failure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "/subjects/test/versions":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[1, 2, 3]`))
+			require.NoError(t, err)
+		case "/subjects/test/versions/1?permanent=false":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`1`))
+			require.NoError(t, err)
+		case "/subjects/test/versions/2?permanent=false":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, err := w.Write([]byte(`{"error_code": 42206, "message": "referenced"}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteAllSchemaVersions(context.Background(), "test")
+
+	assert.EqualValues(t, []int{1}, versions)
+	assert.True(t, HasErrorCode(err, ReferenceExists))
+
+	var multiErr MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Len(t, multiErr, 1)
+	assert.True(t, HasErrorCode(multiErr[SubjectVersion{Subject: "test", Version: 2}], ReferenceExists))
+}
+
+func Test_DeleteAllSchemaVersions_with_a_cancelled_context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.String() != "/subjects/test/versions" {
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		cancel()
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2, 3]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteAllSchemaVersions(ctx, "test")
+
+	assert.Empty(t, versions)
+	assert.Error(t, err)
+}
+
+func Test_GetConfigs_with_mixed_subjects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/config/full":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		case "/config/none":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"compatibilityLevel": "NONE"}`))
+		case "/config/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, err = w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		case "/config/unconfigured":
+			w.WriteHeader(http.StatusNotFound)
+			_, err = w.Write([]byte(`{"error_code": 40408, "message": "subject does not have subject-level compatibility configured"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err = w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConcurrency(2))
+	require.NoError(t, err)
+
+	configs, err := client.GetConfigs(context.Background(), []string{"full", "none", "missing", "unconfigured", "broken"})
+
+	assert.Equal(t, map[string]*Config{
+		"full":         {Compatibility: Full},
+		"none":         {Compatibility: None},
+		"missing":      {},
+		"unconfigured": {},
+	}, configs)
+
+	require.IsType(t, MultiError{}, err)
+	assert.Len(t, err, 1)
+	assert.True(t, HasErrorCode(err.(MultiError)[SubjectVersion{Subject: "broken"}], BackendStoreError))
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+}
+
+func Test_GetConfigs_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "BACKWARD"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	configs, err := client.GetConfigs(context.Background(), []string{"foo", "bar"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*Config{
+		"foo": {Compatibility: Backward},
+		"bar": {Compatibility: Backward},
+	}, configs)
+}
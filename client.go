@@ -3,27 +3,130 @@ package schemaregistry
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// subjectsPageSize is the number of subjects fetched per request by
+// `EachSubject`.
+var subjectsPageSize = 1000
+
+// schemaRegistryMediaType is the media type used by the Schema Registry API.
+const schemaRegistryMediaType = "application/vnd.schemaregistry.v1+json"
+
+// defaultMaxResponseBytes is the default size limit of the response bodies. It
+// is large enough for any real schema.
+const defaultMaxResponseBytes = 32 << 20
+
+// defaultMaxSchemaBytes is the default size limit of the schemas read by
+// `RegisterNewSchemaFromReader`.
+const defaultMaxSchemaBytes = 16 << 20
+
+// defaultAccept lists the media types accepted by default, by order of
+// preference.
+const defaultAccept = schemaRegistryMediaType + ", application/vnd.schemaregistry+json, application/json"
+
 // Option function used to apply modifications to the client.
 type Option func(*Client)
 
+// QueryOption function used to add an optional query parameter to a request.
+// Look at the methods documentation for the supported options.
+type QueryOption func(url.Values)
+
+// WithResolvedFormat requests the schemas with their references inlined, so
+// they are self-contained. It only affects the schemas with references.
+func WithResolvedFormat() QueryOption {
+	return func(query url.Values) {
+		query.Set("format", "resolved")
+	}
+}
+
+// WithDefaultToGlobal requests the effective configuration of a subject: the
+// global configuration is returned instead of an error if the subject has no
+// configuration of its own.
+func WithDefaultToGlobal() QueryOption {
+	return func(query url.Values) {
+		query.Set("defaultToGlobal", "true")
+	}
+}
+
+// WithDeleted requests the soft deleted versions to be included in addition to
+// the active ones.
+func WithDeleted() QueryOption {
+	return func(query url.Values) {
+		query.Set("deleted", "true")
+	}
+}
+
+// WithForce forces a deletion refused by the registry protections, for example
+// a permanent deletion in a mode forbidding it.
+//
+// DANGER: it destroys data the registry was configured to protect, and the
+// permanently deleted schemas can't be recovered. Use it only when the
+// destruction is intended.
+func WithForce() QueryOption {
+	return func(query url.Values) {
+		query.Set("force", "true")
+	}
+}
+
 // Client used to interact with the registry schema REST API.
+//
+// A Client is safe for concurrent use by multiple goroutines. Its settings are
+// not modified once created, the options taking effect only through NewClient.
+// The state shared between the calls is synchronized: the counters of `Stats`,
+// updated atomically, and the opt-in caches (look `UsingConfigCache` and
+// `UsingETagCache`). The documents cached by `Schema.ParseAvro` are shared by
+// the whole process, each call returning its own copy.
 type Client struct {
 	baseURL *url.URL
 
-	client   *http.Client
-	username string
-	password string
+	client     *http.Client
+	ownsClient bool
+
+	schemaValidator  func(string) error
+	contentType      string
+	accept           string
+	defaults         Defaults
+	retry            Retry
+	maxResponseBytes int64
+	maxSchemaBytes   int64
+	concurrency      int
+	strictDecoding   bool
+	configCache      *configCache
+	verboseErrors    bool
+	clock            clock
+	defaultTimeout   time.Duration
+	errorParser      func(*http.Response) error
+	apiPrefix        string
+	host             string
+	headers          http.Header
+	credentials      CredentialsProvider
+	etagCache        *etagCache
+	stats            *clientStats
+
+	compatibilityChecker func(readerSchema string, writerSchema string) (bool, error)
 }
 
+// These are the schema types supported by the registry. A schema without type
+// is an Avro schema.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+	SchemaTypeJSON     = "JSON"
+)
+
 // Schema describes a schema, look `GetSchema` for more.
 type Schema struct {
 	// Schema is the Avro schema string.
@@ -33,38 +136,335 @@ type Schema struct {
 	// Version of the returned schema.
 	Version int `json:"version"`
 	ID      int `json:"id,omitempty"`
+	// SchemaType is one of the SchemaType constants, it is empty for the Avro
+	// schemas on most servers.
+	SchemaType string `json:"schemaType,omitempty"`
+	// References are the schemas imported by this one.
+	References []SchemaReference `json:"references,omitempty"`
+	// GUID is the global identifier of the schema, only returned by the
+	// recent servers.
+	GUID string `json:"guid,omitempty"`
+	// Metadata is the data contract metadata of the version, if any.
+	Metadata *Metadata `json:"metadata,omitempty"`
+	// RuleSet is the data contract rule set of the version, if any.
+	RuleSet *RuleSet `json:"ruleSet,omitempty"`
+}
+
+// SubjectVersion identifies a version of a subject.
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// sortSubjectVersions sorts the pairs by subject, then by version.
+func sortSubjectVersions(subjectVersions []SubjectVersion) {
+	sort.Slice(subjectVersions, func(i, j int) bool {
+		if subjectVersions[i].Subject != subjectVersions[j].Subject {
+			return subjectVersions[i].Subject < subjectVersions[j].Subject
+		}
+		return subjectVersions[i].Version < subjectVersions[j].Version
+	})
+}
+
+// SchemaReference is a reference from a schema to a schema registered under
+// another subject. The name is the one used by the schema to import it (e.g.
+// the Protobuf file name or the Avro type name).
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Defaults describes the default settings applied to the requests per
+// operation class. The reads are the GET requests and the writes are all the
+// others (registrations, deletions, ...).
+type Defaults struct {
+	// ReadTimeout is the timeout applied to the reads, none if zero.
+	ReadTimeout time.Duration
+	// WriteTimeout is the timeout applied to the writes, none if zero.
+	WriteTimeout time.Duration
 }
 
 // Config describes a subject or globa schema-registry configuration
 type Config struct {
 	// Compatibility mode of subject or global
-	Compatibility string `json:"compatibility"`
+	Compatibility CompatibilityLevel `json:"compatibility,omitempty"`
+	// CompatibilityGroup is the metadata property grouping the versions
+	// checked for compatibility, all the versions are checked if empty.
+	CompatibilityGroup string `json:"compatibilityGroup,omitempty"`
+	// DefaultMetadata is the metadata of the new versions registered
+	// without metadata.
+	DefaultMetadata *Metadata `json:"defaultMetadata,omitempty"`
+	// DefaultRuleSet is the rule set of the new versions registered without
+	// rule set.
+	DefaultRuleSet *RuleSet `json:"defaultRuleSet,omitempty"`
+}
+
+// UnmarshalJSON is used to implement the json.Unmarshaler interface. The
+// registry reads the compatibility from the "compatibility" field but returns
+// it in the "compatibilityLevel" field, so both are accepted.
+func (c *Config) UnmarshalJSON(data []byte) error {
+	return c.unmarshal(data, json.Unmarshal)
+}
+
+// unmarshal decodes the configuration with the given decoding function, so
+// that the strict decoding also applies to the fields of the configuration.
+func (c *Config) unmarshal(data []byte, unmarshal func([]byte, interface{}) error) error {
+	type config Config
+
+	var raw struct {
+		config
+		CompatibilityLevel CompatibilityLevel `json:"compatibilityLevel"`
+	}
+
+	err := unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	*c = Config(raw.config)
+	if c.Compatibility == "" {
+		c.Compatibility = raw.CompatibilityLevel
+	}
+
+	return nil
 }
 
 // UsingClient modifies the underline HTTP Client that schema registry is using for contact with the backend server.
 func UsingClient(httpClient *http.Client) Option {
 	return func(c *Client) {
 		c.client = httpClient
+		c.ownsClient = false
+	}
+}
+
+// UsingSchemaValidator sets a function used to validate the schemas before
+// their registration. It allows to catch an invalid schema without a round-trip
+// to the server.
+//
+// No validation is made by default.
+func UsingSchemaValidator(validator func(schema string) error) Option {
+	return func(c *Client) {
+		c.schemaValidator = validator
+	}
+}
+
+// UsingContentType modifies the Content-Type header sent with the request
+// bodies. It defaults to the Schema Registry media type and can be set to
+// "application/json" for the older servers.
+func UsingContentType(contentType string) Option {
+	return func(c *Client) {
+		c.contentType = contentType
+	}
+}
+
+// UsingAccept modifies the Accept header sent with the requests. It defaults
+// to the versioned Schema Registry media type with a fallback on the plain
+// JSON, and can be set to "application/json" for the servers not negotiating
+// the media types.
+func UsingAccept(accept string) Option {
+	return func(c *Client) {
+		c.accept = accept
+	}
+}
+
+// UsingDefaults sets the default settings applied to the requests, look
+// `Defaults` for more.
+func UsingDefaults(defaults Defaults) Option {
+	return func(c *Client) {
+		c.defaults = defaults
+	}
+}
+
+// UsingDefaultTimeout sets the timeout of the requests made with a context
+// without deadline, so they can't hang forever against an unresponsive server.
+// The deadline of the context, if any, always takes precedence.
+//
+// The `Defaults` timeouts still apply on top of it.
+func UsingDefaultTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.defaultTimeout = timeout
+	}
+}
+
+// UsingMaxResponseBytes modifies the size limit of the response bodies, 32MiB
+// by default. A larger response is rejected with an error, protecting the
+// client from a misbehaving server.
+func UsingMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// UsingMaxSchemaBytes modifies the size limit of the schemas read by
+// `RegisterNewSchemaFromReader`, 16MiB by default. A larger schema is rejected
+// with ErrSchemaTooLarge before being sent.
+func UsingMaxSchemaBytes(n int64) Option {
+	return func(c *Client) {
+		c.maxSchemaBytes = n
+	}
+}
+
+// UsingConnectionPool configures the idle connections pool of the HTTP
+// transport: the maximum number of idle connections kept in total and per
+// host, and how long they are kept. The default Go transport keeps only 2 idle
+// connections per host, limiting a client sending many concurrent requests.
+//
+// It is ignored when the HTTP client is provided with `UsingClient`.
+func UsingConnectionPool(maxIdle int, maxIdlePerHost int, idleTimeout time.Duration) Option {
+	return func(c *Client) {
+		if !c.ownsClient {
+			return
+		}
+
+		transport := c.client.Transport.(*http.Transport)
+		transport.MaxIdleConns = maxIdle
+		transport.MaxIdleConnsPerHost = maxIdlePerHost
+		transport.IdleConnTimeout = idleTimeout
+	}
+}
+
+// UsingHTTP1Only disables HTTP/2, for the proxies misbehaving with it. The
+// transport keeps using HTTP/1.1 even if the server offers HTTP/2.
+//
+// It is ignored when the HTTP client is provided with `UsingClient`.
+func UsingHTTP1Only() Option {
+	return func(c *Client) {
+		if !c.ownsClient {
+			return
+		}
+
+		transport := c.client.Transport.(*http.Transport)
+		transport.ForceAttemptHTTP2 = false
+		// A non-nil empty map is required to disable HTTP/2, a nil map
+		// lets the transport configure it.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+}
+
+// UsingRedirectPolicy sets the redirect policy of the HTTP client, see the
+// `http.Client` CheckRedirect field. The redirects are followed up to 10 times
+// by default.
+//
+// It is ignored when the HTTP client is provided with `UsingClient`, its own
+// policy applies.
+func UsingRedirectPolicy(checkRedirect func(req *http.Request, via []*http.Request) error) Option {
+	return func(c *Client) {
+		if c.ownsClient {
+			c.client.CheckRedirect = checkRedirect
+		}
+	}
+}
+
+// UsingVerboseIncompatibility makes RegisterNewSchema explain why a schema is
+// incompatible: when the server refuses it, the compatibility messages are
+// fetched with a second request and an IncompatibleSchemaError is returned.
+//
+// It is disabled by default to avoid the extra round-trip.
+func UsingVerboseIncompatibility() Option {
+	return func(c *Client) {
+		c.verboseErrors = true
+	}
+}
+
+// UsingErrorParser sets a function decoding the error responses, those with a
+// status outside of the 2xx range, for the servers not returning the errors
+// like the Confluent registry. The response body is limited like the others
+// (see `UsingMaxResponseBytes`) and must not be closed.
+//
+// If the parser returns nil, the response is decoded as a Confluent error.
+func UsingErrorParser(parser func(*http.Response) error) Option {
+	return func(c *Client) {
+		c.errorParser = parser
+	}
+}
+
+// UsingAPIPrefix prefixes the path of all the requests, after the base URL
+// path. It allows to target the registries exposing a Confluent compatible API
+// under a sub-path, like the "apis/ccompat/v7" API of Apicurio.
+func UsingAPIPrefix(prefix string) Option {
+	return func(c *Client) {
+		prefix = strings.Trim(prefix, "/")
+		if prefix != "" {
+			prefix += "/"
+		}
+
+		c.apiPrefix = prefix
+	}
+}
+
+// UsingHost sets the Host header of the requests, instead of the host of the
+// base URL. It allows to reach a registry behind a proxy routing on the virtual
+// host, the connection still targeting the base URL.
+func UsingHost(host string) Option {
+	return func(c *Client) {
+		c.host = host
+	}
+}
+
+// UsingHeaders adds the headers to all the requests, e.g. the ones expected by
+// a gateway in front of the registry. The headers managed by the client
+// (Content-Type, Accept, Authorization and If-None-Match) take precedence.
+func UsingHeaders(headers http.Header) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header, len(headers))
+		}
+
+		for name, values := range headers {
+			for _, value := range values {
+				c.headers.Add(name, value)
+			}
+		}
 	}
 }
 
-func WithBasicAuth(user string, password string) Option {
+// UsingAcceptLanguage sets the Accept-Language header of the requests, for the
+// registries localizing their error messages, e.g. "fr-FR, fr;q=0.9".
+func UsingAcceptLanguage(lang string) Option {
 	return func(c *Client) {
-		c.username = user
-		c.password = password
+		if c.headers == nil {
+			c.headers = make(http.Header, 1)
+		}
+
+		c.headers.Set("Accept-Language", lang)
+	}
+}
+
+// UsingStrictDecoding rejects the responses having fields unknown to the
+// client. It is meant for the tests and the CI, to detect a server returning a
+// newer response shape, the unknown fields are ignored by default.
+func UsingStrictDecoding() Option {
+	return func(c *Client) {
+		c.strictDecoding = true
 	}
 }
 
 // NewClient instantiate a new Client.
+//
+// The base URL must be an http or https URL, https is assumed if it has no
+// scheme (e.g. "registry:8081").
 func NewClient(baseURL string, options ...Option) (*Client, error) {
-	url, err := url.Parse(baseURL)
+	url, err := parseBaseURL(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
+	normalizeBaseURL(url)
+
 	client := &Client{
 		baseURL: url,
-		client:  http.DefaultClient,
+		client: &http.Client{
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
+		},
+		ownsClient:       true,
+		contentType:      schemaRegistryMediaType,
+		accept:           defaultAccept,
+		maxResponseBytes: defaultMaxResponseBytes,
+		maxSchemaBytes:   defaultMaxSchemaBytes,
+		concurrency:      defaultConcurrency,
+		clock:            realClock{},
+		stats:            &clientStats{},
 	}
 
 	for _, opt := range options {
@@ -74,41 +474,211 @@ func NewClient(baseURL string, options ...Option) (*Client, error) {
 	return client, nil
 }
 
+// Close releases the idle connections of the underlying HTTP client. It must
+// be called once the client is no longer used in order to not leak them.
+//
+// It does nothing if the HTTP client have been provided with `UsingClient`.
+func (c *Client) Close() {
+	if c.ownsClient {
+		c.client.CloseIdleConnections()
+	}
+}
+
+// parseBaseURL parses the base URL, defaulting to the https scheme, and checks
+// it can be requested.
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	// Without scheme, the host would be parsed as the scheme (e.g.
+	// "localhost:8081"), the path, or refused (e.g. "10.0.0.1:8081"), so the
+	// scheme is added when the URL doesn't parse as "scheme://...".
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme == "" || parsed.Opaque != "" {
+		parsed, err = url.Parse("https://" + rawURL)
+	}
+
+	if err != nil {
+		// The error describes the URL as given, not the defaulted one.
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = rawURL
+		}
+		return nil, err
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %s: unsupported scheme %q, it must be http or https", rawURL, parsed.Scheme)
+	}
+
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %s: missing host", rawURL)
+	}
+
+	return parsed, nil
+}
+
+// normalizeBaseURL ensures the base URL path ends with a slash.
+//
+// The requests paths are relative so without it the last segment of the base
+// URL path would be replaced instead of being kept as a prefix.
+func normalizeBaseURL(baseURL *url.URL) {
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
+
+	// The escaped form must be kept in sync, otherwise it is ignored.
+	if baseURL.RawPath != "" && !strings.HasSuffix(baseURL.RawPath, "/") {
+		baseURL.RawPath += "/"
+	}
+}
+
 // GetSchemaByID returns the Avro schema string identified by the id.
 //
 // https://docs.confluent.io/current/schema-registry/docs/api.html#get--schemas-ids-int-%20id
 func (c *Client) GetSchemaByID(ctx context.Context, subjectID int) (string, error) {
+	ctx = withOperation(ctx, "GetSchemaByID")
+
+	schema, _, err := c.getSchemaByID(ctx, fmt.Sprintf("schemas/ids/%d", subjectID))
+	return schema, err
+}
+
+// GetSchemaByIDTyped returns the schema string identified by the id with its
+// type, one of the SchemaType constants, to select the right decoder. The type
+// is SchemaTypeAvro when the server doesn't return it.
+func (c *Client) GetSchemaByIDTyped(ctx context.Context, id int) (schema string, schemaType string, err error) {
+	ctx = withOperation(ctx, "GetSchemaByIDTyped")
+
+	schema, schemaType, err = c.getSchemaByID(ctx, fmt.Sprintf("schemas/ids/%d", id))
+	if err != nil {
+		return "", "", err
+	}
+
+	if schemaType == "" {
+		schemaType = SchemaTypeAvro
+	}
+
+	return schema, schemaType, nil
+}
+
+// SchemaFromMessage returns the schema used to encode a message framed with the
+// Confluent wire format, and the payload stripped from its header. The schema
+// has its id, type, and a subject/version using it: the first one by order if
+// it is used by several subjects, none if it is used by none.
+//
+// The subject/version lookup is best-effort: it is left empty if it fails, as
+// with the registries not listing the usages of the schemas or the credentials
+// not allowed to. The context errors are returned.
+//
+// Nothing is cached, the usages changing as the subjects register or delete
+// the schema: look `Deserializer` to cache the schemas of the messages.
+//
+// See `DecodePayload` for the errors of the malformed messages.
+func (c *Client) SchemaFromMessage(ctx context.Context, msg []byte) (*Schema, []byte, error) {
+	ctx = withOperation(ctx, "SchemaFromMessage")
+
+	id, payload, err := DecodePayload(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema, schemaType, err := c.GetSchemaByIDTyped(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usage, err := c.messageUsage(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &Schema{Schema: schema, SchemaType: schemaType, ID: id, Subject: usage.Subject, Version: usage.Version}
+
+	return res, payload, nil
+}
+
+// messageUsage returns the first subject/version using the schema, zero if
+// none or if the lookup failed. Only the context errors are returned.
+func (c *Client) messageUsage(ctx context.Context, id int) (SubjectVersion, error) {
+	usages, err := c.SchemaUsages(ctx, id)
+	if ctx.Err() != nil {
+		return SubjectVersion{}, ctx.Err()
+	}
+
+	if err != nil || len(usages) == 0 {
+		return SubjectVersion{}, nil
+	}
+
+	return usages[0], nil
+}
+
+// GetSchemaByGUID returns the schema string identified by the global
+// identifier, see `Schema`. Only the recent servers support it.
+func (c *Client) GetSchemaByGUID(ctx context.Context, guid string) (string, error) {
+	ctx = withOperation(ctx, "GetSchemaByGUID")
+
+	schema, _, err := c.getSchemaByID(ctx, fmt.Sprintf("schemas/guids/%s", url.PathEscape(guid)))
+	return schema, err
+}
+
+// GetSchemaByIDInSubject returns the Avro schema string identified by the id,
+// looked up in the context of the given subject. It disambiguates the IDs in
+// the multi-context deployments where the same ID maps to different schemas.
+func (c *Client) GetSchemaByIDInSubject(ctx context.Context, id int, subject string) (string, error) {
+	ctx = withOperation(ctx, "GetSchemaByIDInSubject")
+
+	schema, _, err := c.getSchemaByID(ctx, fmt.Sprintf("schemas/ids/%d?subject=%s", id, url.QueryEscape(subject)))
+	return schema, err
+}
+
+func (c *Client) getSchemaByID(ctx context.Context, path string) (string, string, error) {
 	type responseBody struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType"`
+		// Ignored, declared to be accepted by the strict decoding.
+		References []SchemaReference `json:"references"`
+		MaxID      int               `json:"maxId"`
+		GUID       string            `json:"guid"`
 	}
 
-	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("schemas/ids/%d", subjectID), nil)
+	rawBody, err := c.execRequest(ctx, "GET", path, nil)
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	if len(rawBody) == 0 {
+		return "", "", ErrEmptyResponse
 	}
 
 	var resBody responseBody
-	err = json.Unmarshal(rawBody, &resBody)
+	err = c.decode(rawBody, &resBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode the response: %s", err)
+		return "", "", fmt.Errorf("failed to decode the response: %s", err)
 	}
 
-	return resBody.Schema, nil
+	return resBody.Schema, resBody.SchemaType, nil
 }
 
-// Subjects returns a list of the available subjects(schemas).
+// SchemaVersionsByID returns all the subject/version pairs associated with the
+// schema identified by the id.
 //
-// https://docs.confluent.io/current/schema-registry/docs/api.html#subjects
-func (c *Client) Subjects(ctx context.Context) (subjects []string, err error) {
-	type responseBody []string
+// https://docs.confluent.io/current/schema-registry/develop/api.html#get--schemas-ids-int-%20id-versions
+func (c *Client) SchemaVersionsByID(ctx context.Context, id int) ([]SubjectVersion, error) {
+	ctx = withOperation(ctx, "SchemaVersionsByID")
 
-	rawBody, err := c.execRequest(ctx, "GET", "subjects", nil)
+	return c.schemaVersionsByID(ctx, id, nil)
+}
+
+func (c *Client) schemaVersionsByID(ctx context.Context, id int, opts []QueryOption) ([]SubjectVersion, error) {
+	type responseBody []SubjectVersion
+
+	rawBody, err := c.execRequest(ctx, "GET", withQuery(fmt.Sprintf("schemas/ids/%d/versions", id), opts), nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
 	var resBody responseBody
-	err = json.Unmarshal(rawBody, &resBody)
+	err = c.decode(rawBody, &resBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode the response: %s", err)
 	}
@@ -116,170 +686,1063 @@ func (c *Client) Subjects(ctx context.Context) (subjects []string, err error) {
 	return resBody, nil
 }
 
-// Versions returns all schema version numbers registered for this subject.
+// VersionsBySchemaIDForSubject returns the versions of the subject associated
+// with the schema identified by the id. See `SchemaVersionsByID` for more.
 //
-// https://docs.confluent.io/current/schema-registry/docs/api.html#get--subjects-(string-%20subject)-versions
-func (c *Client) Versions(ctx context.Context, subject string) (versions []int, err error) {
-	type responseBody []int
+// An empty list is returned if the subject doesn't use the schema.
+func (c *Client) VersionsBySchemaIDForSubject(ctx context.Context, id int, subject string) ([]int, error) {
+	ctx = withOperation(ctx, "VersionsBySchemaIDForSubject")
 
-	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("subjects/%s/versions", subject), nil)
+	subjectVersions, err := c.SchemaVersionsByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	var resBody responseBody
-	err = json.Unmarshal(rawBody, &resBody)
+	versions := []int{}
+	for _, subjectVersion := range subjectVersions {
+		if subjectVersion.Subject == subject {
+			versions = append(versions, subjectVersion.Version)
+		}
+	}
+
+	return versions, nil
+}
+
+// LatestVersionUsingSchema returns the highest version of the subject using
+// the schema identified by the id, a schema registered again after a deletion
+// being used by several versions. See `SchemaVersionsByID` for more.
+//
+// A VersionNotFound ResourceError is returned if the subject doesn't use the
+// schema.
+func (c *Client) LatestVersionUsingSchema(ctx context.Context, id int, subject string) (int, error) {
+	ctx = withOperation(ctx, "LatestVersionUsingSchema")
+
+	versions, err := c.VersionsBySchemaIDForSubject(ctx, id, subject)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode the response: %s", err)
+		return -1, err
 	}
 
-	return resBody, nil
+	latest := -1
+	for _, version := range versions {
+		if version > latest {
+			latest = version
+		}
+	}
+
+	if latest == -1 {
+		return -1, ResourceError{
+			ErrorCode: VersionNotFound,
+			Message:   fmt.Sprintf("Subject '%s' has no version using the schema %d.", subject, id),
+		}
+	}
+
+	return latest, nil
 }
 
-// DeleteSubject deletes the specified subject and its associated compatibility level if registered.
-// It is recommended to use this API only when a topic needs to be recycled or in development environment.
-// Returns the versions of the schema deleted under this subject.
+// SchemaUsages returns the subject/version pairs using the schema identified
+// by the id, grouped by subject and sorted by version. It's meant to assess the
+// impact of deleting or modifying a schema shared by several subjects.
 //
-// https://docs.confluent.io/current/schema-registry/docs/api.html#delete--subjects-(string-%20subject)
-func (c *Client) DeleteSubject(ctx context.Context, subject string, permanent bool) (versions []int, err error) {
-	type responseBody []int
+// It supports the `WithDeleted` option.
+func (c *Client) SchemaUsages(ctx context.Context, id int, opts ...QueryOption) ([]SubjectVersion, error) {
+	ctx = withOperation(ctx, "SchemaUsages")
+
+	subjectVersions, err := c.schemaVersionsByID(ctx, id, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sortSubjectVersions(subjectVersions)
+
+	return subjectVersions, nil
+}
+
+// SubjectsUsingSchema returns the sorted list of the subjects using the schema
+// identified by the id, each subject appearing once. See `SchemaUsages` for more.
+//
+// It supports the `WithDeleted` option.
+func (c *Client) SubjectsUsingSchema(ctx context.Context, id int, opts ...QueryOption) ([]string, error) {
+	ctx = withOperation(ctx, "SubjectsUsingSchema")
+
+	subjectVersions, err := c.SchemaUsages(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := []string{}
+	for i, subjectVersion := range subjectVersions {
+		if i > 0 && subjectVersions[i-1].Subject == subjectVersion.Subject {
+			continue
+		}
+		subjects = append(subjects, subjectVersion.Subject)
+	}
+
+	return subjects, nil
+}
+
+// SubjectsUsingSchemaContent returns the subject/version pairs registering the
+// schema, sorted. Unlike `SchemaUsages` the schema is identified by its
+// content, to find the duplicated registrations.
+//
+// The registry has no endpoint for it: the schema is looked up in every
+// subject, concurrently (look `UsingConcurrency`), then the pairs using the
+// found ids are fetched. The soft deleted subjects and versions are included if
+// includeDeleted is true.
+func (c *Client) SubjectsUsingSchemaContent(ctx context.Context, schema string, includeDeleted bool) ([]SubjectVersion, error) {
+	ctx = withOperation(ctx, "SubjectsUsingSchemaContent")
+
+	var opts []QueryOption
+	if includeDeleted {
+		opts = append(opts, WithDeleted())
+	}
+
+	subjects, err := c.listSubjects(ctx, withQuery("subjects", opts))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(subjects))
+	err = c.forEach(ctx, len(subjects), func(ctx context.Context, i int) error {
+		registered, err := c.lookupSchema(ctx, withQuery(fmt.Sprintf("subjects/%s", escapeSubject(subjects[i])), opts), schema)
+		if IsSchemaNotFound(err) || IsSubjectNotFound(err) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		ids[i] = registered.ID
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The same content has the same id in all the subjects of a registry,
+	// at least unless it has several contexts.
+	seen := map[int]bool{}
+	subjectVersions := []SubjectVersion{}
+
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		usages, err := c.schemaVersionsByID(ctx, id, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		subjectVersions = append(subjectVersions, usages...)
+	}
+
+	sortSubjectVersions(subjectVersions)
+
+	return subjectVersions, nil
+}
+
+// Subjects returns a list of the available subjects(schemas).
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#subjects
+func (c *Client) Subjects(ctx context.Context) (subjects []string, err error) {
+	ctx = withOperation(ctx, "Subjects")
+
+	return c.listSubjects(ctx, "subjects")
+}
+
+func (c *Client) listSubjects(ctx context.Context, path string) ([]string, error) {
+	type responseBody []string
+
+	rawBody, err := c.execRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody, nil
+}
+
+// SubjectsWithPrefix returns the list of the available subjects starting with
+// the given prefix.
+func (c *Client) SubjectsWithPrefix(ctx context.Context, prefix string) (subjects []string, err error) {
+	ctx = withOperation(ctx, "SubjectsWithPrefix")
+
+	type responseBody []string
+
+	rawBody, err := c.execRequest(ctx, "GET", "subjects?subjectPrefix="+url.QueryEscape(prefix), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody, nil
+}
+
+// SubjectsPaged returns a page of the available subjects, starting at offset
+// and containing at most limit subjects.
+//
+// Servers not supporting the pagination ignore the parameters and return all
+// the subjects.
+func (c *Client) SubjectsPaged(ctx context.Context, offset int, limit int) (subjects []string, err error) {
+	ctx = withOperation(ctx, "SubjectsPaged")
+
+	type responseBody []string
+
+	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("subjects?offset=%d&limit=%d", offset, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody, nil
+}
+
+// EachSubject calls fn for each available subject, fetching them page by page
+// with `SubjectsPaged`. It stops at the first error returned by fn.
+func (c *Client) EachSubject(ctx context.Context, fn func(subject string) error) error {
+	ctx = withOperation(ctx, "EachSubject")
+
+	var previous []string
+
+	for offset := 0; ; offset += subjectsPageSize {
+		subjects, err := c.SubjectsPaged(ctx, offset, subjectsPageSize)
+		if err != nil {
+			return err
+		}
+
+		// A server ignoring the pagination returns the same list for each
+		// page. Check it in order to not loop forever.
+		if len(previous) > 0 && len(subjects) > 0 && subjects[0] == previous[0] {
+			return nil
+		}
+
+		for _, subject := range subjects {
+			err = fn(subject)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(subjects) != subjectsPageSize {
+			return nil
+		}
+
+		previous = subjects
+	}
+}
+
+// Versions returns all schema version numbers registered for this subject.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#get--subjects-(string-%20subject)-versions
+func (c *Client) Versions(ctx context.Context, subject string) (versions []int, err error) {
+	ctx = withOperation(ctx, "Versions")
+
+	type responseBody []int
+
+	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("subjects/%s/versions", escapeSubject(subject)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody, nil
+}
+
+// DeleteSubject deletes the specified subject and its associated compatibility level if registered.
+// It is recommended to use this API only when a topic needs to be recycled or in development environment.
+// Returns the versions of the schema deleted under this subject.
+//
+// It supports the `WithForce` option.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#delete--subjects-(string-%20subject)
+func (c *Client) DeleteSubject(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (versions []int, err error) {
+	ctx = withOperation(ctx, "DeleteSubject")
+
+	type responseBody []int
+
+	path := withQuery(fmt.Sprintf("subjects/%s?permanent=%v", escapeSubject(subject), permanent), opts)
+
+	rawBody, err := c.execRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody, nil
+}
+
+// SubjectExists tells if the subject exists, a soft deleted subject not
+// existing. The other errors are returned.
+func (c *Client) SubjectExists(ctx context.Context, subject string) (bool, error) {
+	ctx = withOperation(ctx, "SubjectExists")
+
+	_, err := c.Versions(ctx, subject)
+	if IsSubjectNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteSubjectIfExists soft deletes the subject like `DeleteSubject`, but a
+// subject not existing, or already deleted, isn't an error: deleted is false
+// instead. It makes the teardowns idempotent.
+func (c *Client) DeleteSubjectIfExists(ctx context.Context, subject string) (deleted bool, versions []int, err error) {
+	ctx = withOperation(ctx, "DeleteSubjectIfExists")
+
+	versions, err = c.DeleteSubject(ctx, subject, false)
+	if IsSubjectNotFound(err) || HasErrorCode(err, SubjectSoftDeleted) {
+		return false, nil, nil
+	}
+
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, versions, nil
+}
+
+// IsRegistered tells if the given "schema" is registered for this "subject".
+// The returned error matches `IsSubjectNotFound` if the subject doesn't exist.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#post--subjects-(string-%20subject)
+func (c *Client) IsRegistered(ctx context.Context, subject string, schema string) (bool, *Schema, error) {
+	ctx = withOperation(ctx, "IsRegistered")
+
+	return c.isRegistered(ctx, fmt.Sprintf("subjects/%s", escapeSubject(subject)), schema)
+}
+
+// Lookup returns the version of the subject registering the schema, without
+// registering it. The returned error matches `IsSchemaNotFound` if the schema is
+// not registered, and `IsSubjectNotFound` if the subject doesn't exist.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#post--subjects-(string-%20subject)
+func (c *Client) Lookup(ctx context.Context, subject string, schema string) (*Schema, error) {
+	ctx = withOperation(ctx, "Lookup")
+
+	return c.lookupSchema(ctx, fmt.Sprintf("subjects/%s", escapeSubject(subject)), schema)
+}
+
+// IsRegisteredIncludingDeleted works like `IsRegistered` but also looks for the
+// soft-deleted schemas. The returned Schema keeps the version and the id it had
+// before its deletion.
+//
+// Note that a soft-deleted version is ignored by the compatibility checks, so
+// a schema found with this method is not guaranteed to be compatible with the
+// current versions of the subject.
+func (c *Client) IsRegisteredIncludingDeleted(ctx context.Context, subject string, schema string) (bool, *Schema, error) {
+	ctx = withOperation(ctx, "IsRegisteredIncludingDeleted")
+
+	return c.isRegistered(ctx, fmt.Sprintf("subjects/%s?deleted=true", escapeSubject(subject)), schema)
+}
+
+func (c *Client) isRegistered(ctx context.Context, path string, schema string) (bool, *Schema, error) {
+	registered, err := c.lookupSchema(ctx, path, schema)
+	if IsSchemaNotFound(err) {
+		return false, nil, nil
+	}
+
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, registered, nil
+}
+
+func (c *Client) lookupSchema(ctx context.Context, path string, schema string) (*Schema, error) {
+	type requestBody struct {
+		Schema string `json:"schema"`
+	}
+
+	// nolint
+	// Error not possible here.
+	reqBody, _ := json.Marshal(&requestBody{Schema: schema})
+
+	rawBody, err := c.execRequest(ctx, "POST", path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	var resBody Schema
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return &resBody, nil
+}
+
+// GetIDBySchema returns the global ID of the schema registered for this
+// subject. The returned error matches `IsSchemaNotFound` if the schema is not
+// registered.
+func (c *Client) GetIDBySchema(ctx context.Context, subject string, schema string) (int, error) {
+	ctx = withOperation(ctx, "GetIDBySchema")
+
+	registered, err := c.lookupSchema(ctx, fmt.Sprintf("subjects/%s", escapeSubject(subject)), schema)
+	if err != nil {
+		return -1, err
+	}
+
+	return registered.ID, nil
+}
+
+// GetVersionBySchema returns the version of the subject registering the
+// schema. The returned error matches `IsSchemaNotFound` if the schema is not
+// registered.
+func (c *Client) GetVersionBySchema(ctx context.Context, subject string, schema string) (int, error) {
+	ctx = withOperation(ctx, "GetVersionBySchema")
+
+	registered, err := c.lookupSchema(ctx, fmt.Sprintf("subjects/%s", escapeSubject(subject)), schema)
+	if err != nil {
+		return -1, err
+	}
+
+	return registered.Version, nil
+}
+
+// RegisteredVersion returns the version of the subject registering the schema,
+// ok is false, with a nil error, if the schema is not registered or the subject
+// doesn't exist.
+func (c *Client) RegisteredVersion(ctx context.Context, subject string, schema string) (int, bool, error) {
+	ctx = withOperation(ctx, "RegisteredVersion")
+
+	registered, registeredSchema, err := c.IsRegistered(ctx, subject, schema)
+	if IsSubjectNotFound(err) {
+		return 0, false, nil
+	}
+
+	if err != nil || !registered {
+		return 0, false, err
+	}
+
+	return registeredSchema.Version, true, nil
+}
+
+// RegisterNewSchema registers a schema.
+// The returned identifier should be used to retrieve this schema from the
+// schemas resource and is different from the schema’s version which is
+// associated with that name.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#post--subjects-(string-%20subject)-versions
+func (c *Client) RegisterNewSchema(ctx context.Context, subject string, avroSchema string) (int, error) {
+	ctx = withOperation(ctx, "RegisterNewSchema")
+
+	return c.registerNewSchema(ctx, subject, registration{Schema: avroSchema})
+}
+
+// RegisterNewSchemaWithID works like `RegisterNewSchema` but asks the registry
+// to register the schema with the given id, when it is not zero, instead of
+// assigning the next one. The subject (or the whole registry) must be in the
+// IMPORT mode for the server to accept an explicit id.
+func (c *Client) RegisterNewSchemaWithID(ctx context.Context, subject string, avroSchema string, id int) (int, error) {
+	ctx = withOperation(ctx, "RegisterNewSchemaWithID")
+
+	return c.registerNewSchema(ctx, subject, registration{Schema: avroSchema, ID: id})
+}
+
+// RegisterNewSchemaWithMetadata works like `RegisterNewSchema` for the data
+// contracts of the Confluent registries (7.4+), registering the schema with
+// its metadata and rule set. They are omitted when nil, so the registry
+// applies the defaults of the subject configuration.
+func (c *Client) RegisterNewSchemaWithMetadata(ctx context.Context, subject string, avroSchema string, md *Metadata, rs *RuleSet) (int, error) {
+	ctx = withOperation(ctx, "RegisterNewSchemaWithMetadata")
+
+	return c.registerNewSchema(ctx, subject, registration{Schema: avroSchema, Metadata: md, RuleSet: rs})
+}
+
+// RegisterNewSchemaFromReader works like `RegisterNewSchema` for a schema read
+// from r, of the given type (one of the SchemaType constants, or empty for
+// Avro), like the large schemas stored in files.
+//
+// The schema is limited to 16MiB (see `UsingMaxSchemaBytes`), a larger one is
+// rejected with ErrSchemaTooLarge without reading it entirely.
+func (c *Client) RegisterNewSchemaFromReader(ctx context.Context, subject string, r io.Reader, schemaType string) (int, error) {
+	ctx = withOperation(ctx, "RegisterNewSchemaFromReader")
+
+	// One more byte is read in order to detect the schemas over the limit.
+	rawSchema, err := ioutil.ReadAll(io.LimitReader(r, overLimit(c.maxSchemaBytes)))
+	if err != nil {
+		return -1, fmt.Errorf("failed to read the schema: %s", err)
+	}
+
+	if int64(len(rawSchema)) > c.maxSchemaBytes {
+		return -1, ErrSchemaTooLarge
+	}
+
+	return c.registerNewSchema(ctx, subject, registration{Schema: string(rawSchema), SchemaType: schemaType})
+}
+
+// overLimit returns the number of bytes to read to detect the content over the
+// limit: one more, unless the limit is already the maximum.
+func overLimit(limit int64) int64 {
+	if limit == math.MaxInt64 {
+		return limit
+	}
+
+	return limit + 1
+}
+
+// registration is the body of a schema registration, the optional fields are
+// omitted for the older servers.
+type registration struct {
+	Schema     string    `json:"schema"`
+	SchemaType string    `json:"schemaType,omitempty"`
+	ID         int       `json:"id,omitempty"`
+	Metadata   *Metadata `json:"metadata,omitempty"`
+	RuleSet    *RuleSet  `json:"ruleSet,omitempty"`
+}
+
+// registerNewSchema registers the schema, with the given id if not zero.
+func (c *Client) registerNewSchema(ctx context.Context, subject string, reg registration) (int, error) {
+	type responseBody struct {
+		ID int `json:"id"`
+	}
+
+	if c.schemaValidator != nil {
+		err := c.schemaValidator(reg.Schema)
+		if err != nil {
+			return -1, fmt.Errorf("invalid schema: %s", err)
+		}
+	}
+
+	// nolint
+	// Error not possible here.
+	reqBody, _ := json.Marshal(&reg)
+
+	rawBody, err := c.execRequest(ctx, "POST", fmt.Sprintf("subjects/%s/versions", escapeSubject(subject)), reqBody)
+	if err != nil && c.verboseErrors && IsIncompatibleSchema(err) {
+		return -1, c.explainIncompatibility(ctx, subject, reg.Schema, err)
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	if len(rawBody) == 0 {
+		return -1, ErrEmptyResponse
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return -1, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody.ID, nil
+}
+
+// ImportSchema registers a schema with a specific version and id, keeping the
+// IDs stable during a migration between two registries.
+//
+// The subject (or the whole registry) must be in the IMPORT mode, the error
+// returned by the server is surfaced otherwise.
+func (c *Client) ImportSchema(ctx context.Context, subject string, schema string, version int, id int) (int, error) {
+	ctx = withOperation(ctx, "ImportSchema")
+
+	return c.importSchema(ctx, &Schema{Schema: schema, Subject: subject, Version: version, ID: id})
+}
+
+// importSchema imports the schema with its version, id, type and references.
+func (c *Client) importSchema(ctx context.Context, schema *Schema) (int, error) {
+	type requestBody struct {
+		Schema     string            `json:"schema"`
+		Version    int               `json:"version"`
+		ID         int               `json:"id"`
+		SchemaType string            `json:"schemaType,omitempty"`
+		References []SchemaReference `json:"references,omitempty"`
+		Metadata   *Metadata         `json:"metadata,omitempty"`
+		RuleSet    *RuleSet          `json:"ruleSet,omitempty"`
+	}
+
+	type responseBody struct {
+		ID int `json:"id"`
+	}
+
+	// nolint
+	// Error not possible here.
+	reqBody, _ := json.Marshal(&requestBody{
+		Schema:     schema.Schema,
+		Version:    schema.Version,
+		ID:         schema.ID,
+		SchemaType: schema.SchemaType,
+		References: schema.References,
+		Metadata:   schema.Metadata,
+		RuleSet:    schema.RuleSet,
+	})
+
+	rawBody, err := c.execRequest(ctx, "POST", fmt.Sprintf("subjects/%s/versions", escapeSubject(schema.Subject)), reqBody)
+	if err != nil {
+		return -1, err
+	}
+
+	if len(rawBody) == 0 {
+		return -1, ErrEmptyResponse
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return -1, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody.ID, nil
+}
+
+func (c *Client) getSchemaBySubjectAndVersion(ctx context.Context, subject string, version string, opts []QueryOption) (*Schema, error) {
+	path := withQuery(fmt.Sprintf("subjects/%s/versions/%s", escapeSubject(subject), version), opts)
+
+	rawBody, err := c.execRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	var schema Schema
+	err = c.decode(rawBody, &schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return &schema, nil
+}
+
+// GetSchemaBySubjectAndVersion returns the schema for a particular subject and version.
+//
+// It supports the `WithResolvedFormat` option.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#get--subjects-(string-%20subject)-versions-(versionId-%20version)
+func (c *Client) GetSchemaBySubjectAndVersion(ctx context.Context, subject string, version int, opts ...QueryOption) (*Schema, error) {
+	ctx = withOperation(ctx, "GetSchemaBySubjectAndVersion")
+
+	return c.getSchemaBySubjectAndVersion(ctx, subject, strconv.Itoa(version), opts)
+}
+
+// SchemaID returns the global ID of the schema registered for a particular
+// subject and version. This ID is the one embedded in the wire-format header.
+func (c *Client) SchemaID(ctx context.Context, subject string, version int) (int, error) {
+	ctx = withOperation(ctx, "SchemaID")
+
+	schema, err := c.GetSchemaBySubjectAndVersion(ctx, subject, version)
+	if err != nil {
+		return -1, err
+	}
+
+	return schema.ID, nil
+}
+
+// GetLatestSchema returns the latest version of a schema.
+// See `GetSchemaAtVersion` to retrieve a subject schema by a specific version.
+//
+// It supports the `WithResolvedFormat` option.
+func (c *Client) GetLatestSchema(ctx context.Context, subject string, opts ...QueryOption) (*Schema, error) {
+	ctx = withOperation(ctx, "GetLatestSchema")
+
+	return c.getSchemaBySubjectAndVersion(ctx, subject, "latest", opts)
+}
+
+// IsLatest tells if the schema is the latest version of the subject, the
+// insignificant whitespaces of the schemas being ignored. It is false if the
+// subject doesn't exist.
+func (c *Client) IsLatest(ctx context.Context, subject string, schema string) (bool, error) {
+	ctx = withOperation(ctx, "IsLatest")
+
+	latest, err := c.GetLatestSchema(ctx, subject)
+	if IsSubjectNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return compactSchema(latest.Schema) == compactSchema(schema), nil
+}
+
+// compactSchema removes the insignificant whitespaces of the schema: the
+// ones outside of the strings for the JSON schemas (Avro and JSON Schema), the
+// leading and trailing ones for the others.
+func compactSchema(schema string) string {
+	var compacted bytes.Buffer
+	if json.Compact(&compacted, []byte(schema)) == nil {
+		return compacted.String()
+	}
+
+	return strings.TrimSpace(schema)
+}
+
+// GetLatestSchemaMetadata works like `GetLatestSchema` but guarantees the ID
+// and the SchemaType are populated: the ID is looked up if the server omits it
+// and the type defaults to SchemaTypeAvro.
+func (c *Client) GetLatestSchemaMetadata(ctx context.Context, subject string) (*Schema, error) {
+	ctx = withOperation(ctx, "GetLatestSchemaMetadata")
+
+	schema, err := c.GetLatestSchema(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.ID == 0 {
+		schema.ID, err = c.GetIDBySchema(ctx, subject, schema.Schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if schema.SchemaType == "" {
+		schema.SchemaType = SchemaTypeAvro
+	}
+
+	return schema, nil
+}
+
+func (c *Client) referencedBy(ctx context.Context, subject string, version string) ([]int, error) {
+	type responseBody []int
+
+	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("subjects/%s/versions/%s/referencedby", escapeSubject(subject), version), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return nil, nil
+	}
+
+	var resBody responseBody
+	err = c.decode(rawBody, &resBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody, nil
+}
+
+// ReferencedBy returns the IDs of the schemas referencing a particular subject
+// and version. A schema version can be safely deleted only if this list is
+// empty.
+//
+// https://docs.confluent.io/current/schema-registry/develop/api.html#get--subjects-(string-%20subject)-versions-(versionId-%20version)-referencedby
+func (c *Client) ReferencedBy(ctx context.Context, subject string, version int) ([]int, error) {
+	ctx = withOperation(ctx, "ReferencedBy")
+
+	return c.referencedBy(ctx, subject, strconv.Itoa(version))
+}
+
+// LatestVersion can be passed to the methods documenting it instead of a
+// version number, to designate the latest version of the subject.
+const LatestVersion = -1
+
+// escapeSubject escapes the subject for the request paths. The colons are kept,
+// they delimit the context of the context-qualified subjects (e.g.
+// ":.staging:users-value").
+func escapeSubject(subject string) string {
+	return url.PathEscape(subject)
+}
+
+// formatVersion returns the version as expected in the paths, the latest
+// keyword for LatestVersion.
+func formatVersion(version int) string {
+	if version == LatestVersion {
+		return "latest"
+	}
+
+	return strconv.Itoa(version)
+}
+
+// SubjectsReferencing returns the subject/version pairs of the schemas
+// referencing a particular subject and version, sorted. It resolves the IDs
+// returned by `ReferencedBy` into a readable dependency list.
+//
+// The version can be LatestVersion.
+func (c *Client) SubjectsReferencing(ctx context.Context, subject string, version int) ([]SubjectVersion, error) {
+	ctx = withOperation(ctx, "SubjectsReferencing")
+
+	ids, err := c.referencedBy(ctx, subject, formatVersion(version))
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([][]SubjectVersion, len(ids))
+
+	err = c.forEach(ctx, len(ids), func(ctx context.Context, i int) error {
+		idUsages, err := c.SchemaUsages(ctx, ids[i])
+		usages[i] = idUsages
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	referencing := []SubjectVersion{}
+	for _, idUsages := range usages {
+		referencing = append(referencing, idUsages...)
+	}
+
+	sortSubjectVersions(referencing)
+
+	return referencing, nil
+}
+
+// ReferencedByLatest returns the IDs of the schemas referencing the latest
+// version of a subject. See `ReferencedBy` for more.
+func (c *Client) ReferencedByLatest(ctx context.Context, subject string) ([]int, error) {
+	ctx = withOperation(ctx, "ReferencedByLatest")
+
+	return c.referencedBy(ctx, subject, "latest")
+}
+
+// GetConfig returns the configuration (Config type) for global Schema-Registry or a specific
+// subject. When Config returned has "compatibilityLevel" empty, it's using global settings.
+//
+// It supports the `WithDefaultToGlobal` option. The configurations can be
+// cached, look `UsingConfigCache`.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#get--config-(string-%20subject)
+func (c *Client) GetConfig(ctx context.Context, subject string, opts ...QueryOption) (*Config, error) {
+	ctx = withOperation(ctx, "GetConfig")
 
-	rawBody, err := c.execRequest(ctx, "DELETE", fmt.Sprintf("subjects/%s?permanent=%v", subject, permanent), nil)
-	if err != nil {
-		return nil, err
-	}
+	return c.getConfig(ctx, withQuery(fmt.Sprintf("config/%s", escapeSubject(subject)), opts))
+}
 
-	var resBody responseBody
-	err = json.Unmarshal(rawBody, &resBody)
+// CompatibilityGroup returns the compatibility group of the subject, or the
+// global one if the subject is empty. It is empty if the versions are not
+// grouped.
+func (c *Client) CompatibilityGroup(ctx context.Context, subject string, opts ...QueryOption) (string, error) {
+	ctx = withOperation(ctx, "CompatibilityGroup")
+
+	config, err := c.GetConfig(ctx, subject, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode the response: %s", err)
+		return "", err
 	}
 
-	return resBody, nil
+	return config.CompatibilityGroup, nil
 }
 
-// IsRegistered tells if the given "schema" is registered for this "subject".
-//
-// https://docs.confluent.io/current/schema-registry/docs/api.html#post--subjects-(string-%20subject)
-func (c *Client) IsRegistered(ctx context.Context, subject string, schema string) (bool, *Schema, error) {
-	type requestBody struct {
-		Schema string `json:"schema"`
-	}
-
-	// nolint
-	// Error not possible here.
-	reqBody, _ := json.Marshal(&requestBody{Schema: schema})
+// EffectiveCompatibility returns the compatibility level applied to the
+// subject: its own level, or the global one if the subject has none.
+func (c *Client) EffectiveCompatibility(ctx context.Context, subject string) (CompatibilityLevel, error) {
+	ctx = withOperation(ctx, "EffectiveCompatibility")
 
-	rawBody, err := c.execRequest(ctx, "POST", fmt.Sprintf("subjects/%s", subject), bytes.NewReader(reqBody))
-	if IsSchemaNotFound(err) || IsSchemaNotFound(err) {
-		return false, nil, nil
+	config, err := c.GetConfig(ctx, subject)
+	if err != nil && !IsSubjectNotFound(err) && !HasErrorCode(err, SubjectLevelCompatibilityNotConfigured) {
+		return "", err
 	}
 
-	if err != nil {
-		return false, nil, err
+	if err == nil && config.Compatibility != "" {
+		return config.Compatibility, nil
 	}
 
-	var resBody Schema
-	err = json.Unmarshal(rawBody, &resBody)
+	config, err = c.getConfig(ctx, "config")
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to decode the response: %s", err)
+		return "", err
 	}
 
-	return true, &resBody, nil
+	return config.Compatibility, nil
 }
 
-// RegisterNewSchema registers a schema.
-// The returned identifier should be used to retrieve this schema from the
-// schemas resource and is different from the schema’s version which is
-// associated with that name.
-//
-// https://docs.confluent.io/current/schema-registry/docs/api.html#post--subjects-(string-%20subject)-versions
-func (c *Client) RegisterNewSchema(ctx context.Context, subject string, avroSchema string) (int, error) {
-	type requestBody struct {
-		Schema string `json:"schema"`
-	}
-
-	type responseBody struct {
-		ID int `json:"id"`
+// getConfig gets the configuration at the path, from the cache if enabled.
+func (c *Client) getConfig(ctx context.Context, path string) (*Config, error) {
+	if c.configCache != nil {
+		config, ok := c.configCache.get(path, c.clock.Now())
+		c.stats.countCacheLookup(ok)
+		if ok {
+			return config, nil
+		}
 	}
 
-	// nolint
-	// Error not possible here.
-	reqBody, _ := json.Marshal(&requestBody{Schema: avroSchema})
-
-	rawBody, err := c.execRequest(ctx, "POST", fmt.Sprintf("subjects/%s/versions", subject), bytes.NewReader(reqBody))
+	config, err := c.fetchConfig(ctx, path)
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
 
-	var resBody responseBody
-	err = json.Unmarshal(rawBody, &resBody)
-	if err != nil {
-		return -1, fmt.Errorf("failed to decode the response: %s", err)
+	if c.configCache != nil {
+		c.configCache.set(path, *config, c.clock.Now())
 	}
 
-	return resBody.ID, nil
+	return config, nil
 }
 
-func (c *Client) getSchemaBySubjectAndVersion(ctx context.Context, subject string, version string) (*Schema, error) {
-	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("subjects/%s/versions/%s", subject, version), nil)
+// fetchConfig gets the configuration from the server, bypassing the cache.
+func (c *Client) fetchConfig(ctx context.Context, path string) (*Config, error) {
+	rawBody, err := c.execRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var schema Schema
-	err = json.Unmarshal(rawBody, &schema)
+	if len(rawBody) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	var config Config
+	err = c.decode(rawBody, &config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode the response: %s", err)
 	}
 
-	return &schema, nil
+	return &config, nil
 }
 
-// GetSchemaBySubjectAndVersion returns the schema for a particular subject and version.
+// SetGlobalConfig updates the global configuration and returns the new one.
+// The compatibility level is checked before sending the request.
 //
-// https://docs.confluent.io/current/schema-registry/docs/api.html#get--subjects-(string-%20subject)-versions-(versionId-%20version)
-func (c *Client) GetSchemaBySubjectAndVersion(ctx context.Context, subject string, version int) (*Schema, error) {
-	return c.getSchemaBySubjectAndVersion(ctx, subject, strconv.Itoa(version))
+// https://docs.confluent.io/current/schema-registry/docs/api.html#put--config
+func (c *Client) SetGlobalConfig(ctx context.Context, config Config) (*Config, error) {
+	ctx = withOperation(ctx, "SetGlobalConfig")
+
+	return c.setConfig(ctx, "config", config)
 }
 
-// GetLatestSchema returns the latest version of a schema.
-// See `GetSchemaAtVersion` to retrieve a subject schema by a specific version.
-func (c *Client) GetLatestSchema(ctx context.Context, subject string) (*Schema, error) {
-	return c.getSchemaBySubjectAndVersion(ctx, subject, "latest")
+// SetConfig updates the configuration of a specific subject and returns the
+// new one. The compatibility level is checked before sending the request, it
+// can be empty to only update the other fields.
+//
+// https://docs.confluent.io/current/schema-registry/docs/api.html#put--config-(string-%20subject)
+func (c *Client) SetConfig(ctx context.Context, subject string, config Config) (*Config, error) {
+	ctx = withOperation(ctx, "SetConfig")
+
+	return c.setConfig(ctx, fmt.Sprintf("config/%s", escapeSubject(subject)), config)
 }
 
-// GetConfig returns the configuration (Config type) for global Schema-Registry or a specific
-// subject. When Config returned has "compatibilityLevel" empty, it's using global settings.
+// These values bound the reads made by SetAndVerifyConfig to see the new
+// configuration.
+const (
+	verifyConfigAttempts = 5
+	verifyConfigDelay    = 200 * time.Millisecond
+)
+
+// SetAndVerifyConfig sets the compatibility level of the subject, like
+// `SetConfig`, then reads it back until the change is visible. The clustered
+// registries apply the changes asynchronously, so a read following a write
+// could return the previous level.
 //
-// https://docs.confluent.io/current/schema-registry/docs/api.html#get--config-(string-%20subject)
-func (c *Client) GetConfig(ctx context.Context, subject string) (*Config, error) {
-	rawBody, err := c.execRequest(ctx, "GET", fmt.Sprintf("config/%s", subject), nil)
+// The level is read up to 5 times, 200ms apart, before giving up with an
+// error.
+func (c *Client) SetAndVerifyConfig(ctx context.Context, subject string, level CompatibilityLevel) error {
+	ctx = withOperation(ctx, "SetAndVerifyConfig")
+
+	if !level.Valid() {
+		return fmt.Errorf("invalid compatibility level %q", level)
+	}
+
+	path := fmt.Sprintf("config/%s", escapeSubject(subject))
+
+	_, err := c.setConfig(ctx, path, Config{Compatibility: level})
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	var config *Config
+	for attempt := 1; ; attempt++ {
+		config, err = c.fetchConfig(ctx, path)
+		if err != nil && !IsSubjectNotFound(err) {
+			return err
+		}
+
+		if err == nil && config.Compatibility == level {
+			return nil
+		}
+
+		if attempt == verifyConfigAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock.After(verifyConfigDelay):
+		}
 	}
 
-	var config Config
-	err = json.Unmarshal(rawBody, &config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode the response: %s", err)
+		return fmt.Errorf("the compatibility level of %s is not visible after %d reads: %s", subject, verifyConfigAttempts, err)
 	}
 
-	return &config, nil
+	return fmt.Errorf("the compatibility level of %s is still %s instead of %s after %d reads", subject, config.Compatibility, level, verifyConfigAttempts)
 }
 
-func (c *Client) SetGlobalConfig(ctx context.Context, config Config) (*Config, error) {
+func (c *Client) setConfig(ctx context.Context, path string, config Config) (*Config, error) {
+	// The level is optional, the registry only updates the fields sent.
+	if config.Compatibility != "" && !config.Compatibility.Valid() {
+		return nil, fmt.Errorf("invalid compatibility level %q", config.Compatibility)
+	}
+
 	// nolint
 	// Error not possible here.
 	reqBody, _ := json.Marshal(&config)
 
-	rawBody, err := c.execRequest(ctx, "PUT", "config", bytes.NewReader(reqBody))
+	rawBody, err := c.execRequest(ctx, "PUT", path, reqBody)
+	if c.configCache != nil {
+		c.configCache.clear()
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
+	if len(rawBody) == 0 {
+		return &config, nil
+	}
+
 	var newConfig Config
-	err = json.Unmarshal(rawBody, &newConfig)
+	err = c.decode(rawBody, &newConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode the response: %s", err)
 	}
@@ -287,14 +1750,20 @@ func (c *Client) SetGlobalConfig(ctx context.Context, config Config) (*Config, e
 	return &newConfig, nil
 }
 
-func (c *Client) deleteSchemaVersion(ctx context.Context, subject string, version string, permanent bool) (int, error) {
-	rawBody, err := c.execRequest(ctx, "DELETE", fmt.Sprintf("subjects/%s/versions/%s?permanent=%v", subject, version, permanent), nil)
+func (c *Client) deleteSchemaVersion(ctx context.Context, subject string, version string, permanent bool, opts []QueryOption) (int, error) {
+	path := withQuery(fmt.Sprintf("subjects/%s/versions/%s?permanent=%v", escapeSubject(subject), version, permanent), opts)
+
+	rawBody, err := c.execRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return -1, err
 	}
 
+	if len(rawBody) == 0 {
+		return 0, nil
+	}
+
 	var id int
-	err = json.Unmarshal(rawBody, &id)
+	err = c.decode(rawBody, &id)
 	if err != nil {
 		return -1, fmt.Errorf("failed to decode the response: %s", err)
 	}
@@ -312,16 +1781,24 @@ func (c *Client) deleteSchemaVersion(ctx context.Context, subject string, versio
 // its required to delete a previously registered schema for compatibility
 // purposes or re-register previously registered schema.
 //
+// It supports the `WithForce` option.
+//
 // https://docs.confluent.io/current/schema-registry/docs/api.html#delete--subjects-(string-%20subject)-versions-(versionId-%20version)
-func (c *Client) DeleteSchemaVersion(ctx context.Context, subject string, version int, permanent bool) (int, error) {
-	return c.deleteSchemaVersion(ctx, subject, strconv.Itoa(version), permanent)
+func (c *Client) DeleteSchemaVersion(ctx context.Context, subject string, version int, permanent bool, opts ...QueryOption) (int, error) {
+	ctx = withOperation(ctx, "DeleteSchemaVersion")
+
+	return c.deleteSchemaVersion(ctx, subject, strconv.Itoa(version), permanent, opts)
 }
 
 // DeleteLatestSchemaVersion remove the latest version of a schema.
 //
 // See `DeleteLatestSchemaVersion` to retrieve a subject schema by a specific version.
-func (c *Client) DeleteLatestSchemaVersion(ctx context.Context, subject string, permanent bool) (int, error) {
-	return c.deleteSchemaVersion(ctx, subject, "latest", permanent)
+//
+// It supports the `WithForce` option.
+func (c *Client) DeleteLatestSchemaVersion(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (int, error) {
+	ctx = withOperation(ctx, "DeleteLatestSchemaVersion")
+
+	return c.deleteSchemaVersion(ctx, subject, "latest", permanent, opts)
 }
 
 // SchemaCompatibleWith test input schema against a particular version of a subject's
@@ -332,37 +1809,297 @@ func (c *Client) DeleteLatestSchemaVersion(ctx context.Context, subject string,
 // If this subject's compatibility level was never changed, then the global
 // compatibility level applies (http:get:: /config).
 //
+// The version can be LatestVersion.
+//
 // https://docs.confluent.io/current/schema-registry/docs/api.html#post--compatibility-subjects-(string-%20subject)-versions-(versionId-%20version)
 func (c *Client) SchemaCompatibleWith(ctx context.Context, schema string, subject string, version int) (bool, error) {
-	type requestBody struct {
-		Schema string `json:"schema"`
+	ctx = withOperation(ctx, "SchemaCompatibleWith")
+
+	return c.schemaCompatibleWith(ctx, schema, "", fmt.Sprintf("compatibility/subjects/%s/versions/%s", escapeSubject(subject), formatVersion(version)))
+}
+
+// SchemaCompatibleWithLatest test input schema against the latest version of
+// a subject's schema for compatibility.
+func (c *Client) SchemaCompatibleWithLatest(ctx context.Context, schema string, subject string) (bool, error) {
+	ctx = withOperation(ctx, "SchemaCompatibleWithLatest")
+
+	return c.SchemaCompatibleWith(ctx, schema, subject, LatestVersion)
+}
+
+// TypedSchemaCompatibleWith works like `SchemaCompatibleWith` for the schemas
+// of the given type (one of the SchemaType constants), allowing to check the
+// JSON Schema and Protobuf schemas.
+func (c *Client) TypedSchemaCompatibleWith(ctx context.Context, schema string, schemaType string, subject string, version int) (bool, error) {
+	ctx = withOperation(ctx, "TypedSchemaCompatibleWith")
+
+	return c.schemaCompatibleWith(ctx, schema, schemaType, fmt.Sprintf("compatibility/subjects/%s/versions/%s", escapeSubject(subject), formatVersion(version)))
+}
+
+// SchemaCompatibleWithSubject test input schema against the versions of a
+// subject's schema selected by the subject compatibility level. For example
+// all the versions are checked for the transitive levels.
+//
+// https://docs.confluent.io/current/schema-registry/develop/api.html#post--compatibility-subjects-(string-%20subject)-versions
+func (c *Client) SchemaCompatibleWithSubject(ctx context.Context, schema string, subject string) (bool, error) {
+	ctx = withOperation(ctx, "SchemaCompatibleWithSubject")
+
+	return c.schemaCompatibleWith(ctx, schema, "", fmt.Sprintf("compatibility/subjects/%s/versions", escapeSubject(subject)))
+}
+
+func (c *Client) schemaCompatibleWith(ctx context.Context, schema string, schemaType string, path string) (bool, error) {
+	compatible, _, err := c.checkCompatibility(ctx, CompatibilityRequest{Schema: schema, SchemaType: schemaType}, path)
+	return compatible, err
+}
+
+// CompatibilityRequest describes a schema to check with
+// `SchemaCompatibleWithFull`.
+type CompatibilityRequest struct {
+	Schema string `json:"schema"`
+	// SchemaType is one of the SchemaType constants, empty for Avro.
+	SchemaType string `json:"schemaType,omitempty"`
+	// References are the schemas imported by this one.
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+// SchemaCompatibilityResult is the result of a compatibility check.
+type SchemaCompatibilityResult struct {
+	IsCompatible bool
+	// Messages explain the incompatibility, they are empty if the server
+	// doesn't support the verbose compatibility checks.
+	Messages []string
+}
+
+// SchemaCompatibleWithFull works like `SchemaCompatibleWith` for the schemas
+// described with their type and references, as the composed Protobuf and JSON
+// Schema schemas. The messages of the server explaining an incompatibility are
+// returned too. The version can be LatestVersion.
+func (c *Client) SchemaCompatibleWithFull(ctx context.Context, req CompatibilityRequest, subject string, version int) (*SchemaCompatibilityResult, error) {
+	ctx = withOperation(ctx, "SchemaCompatibleWithFull")
+
+	path := fmt.Sprintf("compatibility/subjects/%s/versions/%s?verbose=true", escapeSubject(subject), formatVersion(version))
+
+	compatible, messages, err := c.checkCompatibility(ctx, req, path)
+	if err != nil {
+		return nil, err
 	}
 
+	return &SchemaCompatibilityResult{IsCompatible: compatible, Messages: messages}, nil
+}
+
+// checkCompatibility returns the compatibility of the schema and, if the
+// server sent them, the messages explaining an incompatibility.
+func (c *Client) checkCompatibility(ctx context.Context, req CompatibilityRequest, path string) (bool, []string, error) {
 	type responseBody struct {
-		IsCompatible bool `json:"is_compatible"`
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+
+	// The Avro schemas are sent without type for the servers not supporting
+	// the other types.
+	if req.SchemaType == SchemaTypeAvro {
+		req.SchemaType = ""
 	}
 
 	// nolint
 	// Error not possible here.
-	reqBody, _ := json.Marshal(&requestBody{Schema: schema})
+	reqBody, _ := json.Marshal(&req)
 
-	rawBody, err := c.execRequest(ctx, "POST", fmt.Sprintf("compatibility/subjects/%s/versions/%d", subject, version), bytes.NewReader(reqBody))
+	rawBody, err := c.execRequest(ctx, "POST", path, reqBody)
 	if err != nil {
-		return false, err
+		return false, nil, err
+	}
+
+	if len(rawBody) == 0 {
+		return false, nil, ErrEmptyResponse
 	}
 
 	var resBody responseBody
-	err = json.Unmarshal(rawBody, &resBody)
+	err = c.decode(rawBody, &resBody)
 	if err != nil {
-		return false, fmt.Errorf("failed to decode the response: %s", err)
+		return false, nil, fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return resBody.IsCompatible, resBody.Messages, nil
+}
+
+// CanRegister tells, without any modification, if the schema would be accepted
+// by a registration for this subject: either it is already registered,
+// or it is compatible with the subject versions.
+//
+// A schema is always compatible with a subject not existing yet.
+func (c *Client) CanRegister(ctx context.Context, subject string, schema string) (alreadyRegistered bool, compatible bool, err error) {
+	ctx = withOperation(ctx, "CanRegister")
+
+	alreadyRegistered, _, err = c.IsRegistered(ctx, subject, schema)
+	if IsSubjectNotFound(err) {
+		return false, true, nil
+	}
+
+	if err != nil {
+		return false, false, err
+	}
+
+	if alreadyRegistered {
+		return true, true, nil
+	}
+
+	compatible, err = c.SchemaCompatibleWithSubject(ctx, schema, subject)
+	if IsSubjectNotFound(err) {
+		return false, true, nil
+	}
+
+	if err != nil {
+		return false, false, err
+	}
+
+	return false, compatible, nil
+}
+
+// RegisterIfCompatible registers the schema only if it is compatible with the
+// subject versions selected by the subject compatibility level. An
+// IncompatibleSchemaError is returned otherwise, with the messages of the
+// server explaining why.
+//
+// The server checks accept anything for a subject with the NONE level. With a
+// checker set by `UsingCompatibilityChecker`, such a subject is checked on the
+// client side instead: the schema must be able to read the latest version, as
+// with the BACKWARD level. Without checker, the NONE subjects are not guarded.
+//
+// A schema is always registered for a subject not existing yet.
+func (c *Client) RegisterIfCompatible(ctx context.Context, subject string, schema string) (int, error) {
+	ctx = withOperation(ctx, "RegisterIfCompatible")
+
+	path := fmt.Sprintf("compatibility/subjects/%s/versions?verbose=true", escapeSubject(subject))
+
+	compatible, messages, err := c.checkCompatibility(ctx, CompatibilityRequest{Schema: schema}, path)
+	if err != nil && !IsSubjectNotFound(err) {
+		return -1, err
+	}
+
+	if err == nil && !compatible {
+		return -1, IncompatibleSchemaError{Subject: subject, Messages: messages}
+	}
+
+	if err == nil && c.compatibilityChecker != nil {
+		err = c.checkNoneCompatibility(ctx, subject, schema)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	return c.RegisterNewSchema(ctx, subject, schema)
+}
+
+// checkNoneCompatibility checks on the client side that the schema can read
+// the latest version of the subject, if the subject has the NONE level.
+func (c *Client) checkNoneCompatibility(ctx context.Context, subject string, schema string) error {
+	level, err := c.EffectiveCompatibility(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	if level != None {
+		return nil
+	}
+
+	latest, err := c.GetLatestSchema(ctx, subject)
+	if IsSubjectNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	compatible, err := c.AreCompatible(ctx, schema, latest.Schema)
+	if err != nil {
+		return err
+	}
+
+	if !compatible {
+		return IncompatibleSchemaError{
+			Subject:  subject,
+			Messages: []string{fmt.Sprintf("the schema can't read the version %d of the subject with the NONE level", latest.Version)},
+		}
+	}
+
+	return nil
+}
+
+// explainIncompatibility wraps the error of a refused registration into an
+// IncompatibleSchemaError with the compatibility messages. The error is
+// returned unchanged if they can't be fetched.
+func (c *Client) explainIncompatibility(ctx context.Context, subject string, schema string, err error) error {
+	path := fmt.Sprintf("compatibility/subjects/%s/versions?verbose=true", escapeSubject(subject))
+
+	_, messages, checkErr := c.checkCompatibility(ctx, CompatibilityRequest{Schema: schema}, path)
+	if checkErr != nil {
+		return err
+	}
+
+	return IncompatibleSchemaError{Subject: subject, Messages: messages, Err: err}
+}
+
+// Do sends a request to an arbitrary endpoint and decodes the JSON response
+// into out, unless it is nil or the response is empty. The path is relative to
+// the base URL.
+//
+// This is an advanced escape hatch for the endpoints not wrapped by the
+// client: the requests benefit from the authentication, the retries and the
+// error parsing but nothing is checked on the path, the body or the response
+// shape.
+func (c *Client) Do(ctx context.Context, method string, path string, body io.Reader, out interface{}) error {
+	ctx = withOperation(ctx, "Do")
+
+	// The body is kept in order to be sent again in case of retry.
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	rawBody, err := c.execRequest(ctx, method, path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(rawBody) == 0 {
+		return nil
+	}
+
+	err = c.decode(rawBody, out)
+	if err != nil {
+		return fmt.Errorf("failed to decode the response: %s", err)
+	}
+
+	return nil
+}
+
+// withQuery appends to the path the query parameters set by the options.
+func withQuery(path string, opts []QueryOption) string {
+	if len(opts) == 0 {
+		return path
+	}
+
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&" + query.Encode()
 	}
 
-	return resBody.IsCompatible, nil
+	return path + "?" + query.Encode()
 }
 
 // Execute the request and check for an error into the response.
 //
-// In case of succes it return the raw body.
+// In case of succes it return the raw body. The body is empty for the
+// "204 No Content" responses.
 //
 // It return an error if:
 // - an error occure with the network
@@ -370,35 +2107,191 @@ func (c *Client) SchemaCompatibleWith(ctx context.Context, schema string, subjec
 // - the request the params have an invalid
 // - the response have an invalid format
 // - the response is an error
-func (c *Client) execRequest(ctx context.Context, method string, rawPath string, body io.Reader) ([]byte, error) {
-	path, err := url.Parse(rawPath)
+func (c *Client) execRequest(ctx context.Context, method string, rawPath string, reqBody []byte) ([]byte, error) {
+	path, err := url.Parse(c.apiPrefix + rawPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, ok := ctx.Deadline(); !ok && c.defaultTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.defaultTimeout)
+		defer cancel()
+	}
+
+	timeout := c.defaults.WriteTimeout
+	if method == "GET" {
+		timeout = c.defaults.ReadTimeout
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		atomic.AddInt64(&c.stats.requests, 1)
+		if attempt > 1 {
+			atomic.AddInt64(&c.stats.retries, 1)
+		}
+
+		rawBody, statusCode, err := c.doRequest(ctx, method, c.baseURL.ResolveReference(path).String(), reqBody)
+		if err == nil {
+			return rawBody, nil
+		}
+
+		if !c.retry.shouldRetry(ctx, method, statusCode, attempt) {
+			atomic.AddInt64(&c.stats.errors, 1)
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			atomic.AddInt64(&c.stats.errors, 1)
+			return nil, err
+		case <-c.clock.After(c.retry.delay(attempt)):
+		}
+	}
+}
+
+// decode decodes a response body into v, rejecting the unknown fields if the
+// strict decoding is enabled.
+func (c *Client) decode(rawBody []byte, v interface{}) error {
+	if !c.strictDecoding {
+		return json.Unmarshal(rawBody, v)
+	}
+
+	// Config implements json.Unmarshaler, which would skip the strict decoding
+	if config, ok := v.(*Config); ok {
+		return config.unmarshal(rawBody, strictUnmarshal)
+	}
+
+	return strictUnmarshal(rawBody, v)
+}
+
+// strictUnmarshal decodes data into v, rejecting the unknown fields.
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(v)
+}
+
+// doRequest sends a single request. The response status code is returned with
+// the error in order to decide if the request must be retried, it is zero if
+// no response have been received.
+func (c *Client) doRequest(ctx context.Context, method string, url string, body []byte) ([]byte, int, error) {
+	// A nil reader, not a nil *bytes.Reader, for the request to have no body.
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
 	// nolint
 	// The request is always valid
-	req, _ := http.NewRequest(method, c.baseURL.ResolveReference(path).String(), body)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Accept", "application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, application/json")
+	req, _ := http.NewRequest(method, url, reqBody)
+
+	for name, values := range c.headers {
+		req.Header[name] = append([]string(nil), values...)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", c.contentType)
+	}
+	req.Header.Set("Accept", c.accept)
+
+	if c.host != "" {
+		req.Host = c.host
+	}
+
+	if c.credentials != nil {
+		token, err := c.credentials.Token(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get the credentials: %s", err)
+		}
 
-	req.SetBasicAuth(c.username, c.password)
+		req.Header.Set("Authorization", token)
+	}
+
+	var cached etagCacheEntry
+	conditional := false
+	if c.etagCache != nil && method == "GET" {
+		cached, conditional = c.etagCache.get(url)
+		if conditional {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
 
 	res, err := c.client.Do(req.WithContext(ctx))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer res.Body.Close()
 
-	err = parseResponseError(req, res)
+	if c.etagCache != nil && method == "GET" {
+		notModified := conditional && res.StatusCode == http.StatusNotModified
+		c.stats.countCacheLookup(notModified)
+		if notModified {
+			return cached.body, http.StatusOK, nil
+		}
+	}
+
+	// One more byte is read in order to detect the bodies over the limit.
+	res.Body = ioutil.NopCloser(io.LimitReader(res.Body, overLimit(c.maxResponseBytes)))
+
+	err = c.parseError(req, res)
 	if err != nil {
-		return nil, err
+		return nil, res.StatusCode, err
+	}
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil, res.StatusCode, nil
+	}
+
+	if isHTML(res.Header.Get("Content-Type")) {
+		return nil, res.StatusCode, UnexpectedContentTypeError{
+			ContentType: res.Header.Get("Content-Type"),
+			Method:      req.Method,
+			URI:         req.URL.String(),
+		}
 	}
 
 	rawBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return nil, err
+		return nil, res.StatusCode, err
+	}
+
+	if int64(len(rawBody)) > c.maxResponseBytes {
+		return nil, res.StatusCode, fmt.Errorf("the response body exceeds the limit of %d bytes", c.maxResponseBytes)
+	}
+
+	if c.etagCache != nil && method == "GET" {
+		c.etagCache.store(url, res, rawBody)
+	}
+
+	return rawBody, res.StatusCode, nil
+}
+
+// parseError returns the error described by the response, if any, using the
+// parser set by `UsingErrorParser` first.
+func (c *Client) parseError(req *http.Request, res *http.Response) error {
+	if c.errorParser == nil || (res.StatusCode >= 200 && res.StatusCode < 300) {
+		return parseResponseError(req, res, c.maxResponseBytes)
+	}
+
+	// The body is kept in order to be decoded again by the default parser.
+	rawBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(rawBody))
+	err = c.errorParser(res)
+	if err != nil {
+		return err
 	}
 
-	return rawBody, nil
+	res.Body = ioutil.NopCloser(bytes.NewReader(rawBody))
+	return parseResponseError(req, res, c.maxResponseBytes)
 }
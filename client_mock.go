@@ -2,25 +2,162 @@ package schemaregistry
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
 
 // ClientMock is a mock implementation of Client.
+//
+// By default the contexts are not part of the call arguments so the
+// expectations are set without them:
+//
+//	m := new(ClientMock)
+//	m.On("GetConfig", "some-subject")
+//
+// In the strict mode the context is the first argument, allowing to assert
+// the contexts passed by the caller:
+//
+//	m := NewStrictClientMock()
+//	m.On("GetConfig", mock.Anything, "some-subject")
 type ClientMock struct {
 	mock.Mock
+
+	// StrictContext adds the context as the first argument of the calls.
+	StrictContext bool
+}
+
+// NewStrictClientMock instantiate a new ClientMock in the strict mode, look
+// `ClientMock` for more.
+func NewStrictClientMock() *ClientMock {
+	return &ClientMock{StrictContext: true}
+}
+
+// called registers the call, with the context in the strict mode.
+func (c *ClientMock) called(ctx context.Context, method string, args ...interface{}) mock.Arguments {
+	if c.StrictContext {
+		args = append([]interface{}{ctx}, args...)
+	}
+
+	return c.MethodCalled(method, args...)
 }
 
 // GetSchemaByID method mock
 func (c *ClientMock) GetSchemaByID(ctx context.Context, subjectID int) (string, error) {
-	args := c.Called(subjectID)
+	args := c.called(ctx, "GetSchemaByID", subjectID)
 
 	return args.String(0), args.Error(1)
 }
 
+// GetSchemaByIDTyped method mock
+func (c *ClientMock) GetSchemaByIDTyped(ctx context.Context, id int) (schema string, schemaType string, err error) {
+	args := c.called(ctx, "GetSchemaByIDTyped", id)
+
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+// SchemaFromMessage method mock
+func (c *ClientMock) SchemaFromMessage(ctx context.Context, msg []byte) (*Schema, []byte, error) {
+	args := c.called(ctx, "SchemaFromMessage", msg)
+
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+
+	return args.Get(0).(*Schema), args.Get(1).([]byte), args.Error(2)
+}
+
+// GetSchemaByGUID method mock
+func (c *ClientMock) GetSchemaByGUID(ctx context.Context, guid string) (string, error) {
+	args := c.called(ctx, "GetSchemaByGUID", guid)
+
+	return args.String(0), args.Error(1)
+}
+
+// GetSchemaByIDInSubject method mock
+func (c *ClientMock) GetSchemaByIDInSubject(ctx context.Context, id int, subject string) (string, error) {
+	args := c.called(ctx, "GetSchemaByIDInSubject", id, subject)
+
+	return args.String(0), args.Error(1)
+}
+
+// SchemaVersionsByID method mock
+func (c *ClientMock) SchemaVersionsByID(ctx context.Context, id int) ([]SubjectVersion, error) {
+	args := c.called(ctx, "SchemaVersionsByID", id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]SubjectVersion), args.Error(1)
+}
+
+// VersionsBySchemaIDForSubject method mock
+func (c *ClientMock) VersionsBySchemaIDForSubject(ctx context.Context, id int, subject string) ([]int, error) {
+	args := c.called(ctx, "VersionsBySchemaIDForSubject", id, subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// LatestVersionUsingSchema method mock
+func (c *ClientMock) LatestVersionUsingSchema(ctx context.Context, id int, subject string) (int, error) {
+	args := c.called(ctx, "LatestVersionUsingSchema", id, subject)
+
+	return args.Int(0), args.Error(1)
+}
+
+// SchemaUsages method mock, the options are ignored.
+func (c *ClientMock) SchemaUsages(ctx context.Context, id int, opts ...QueryOption) ([]SubjectVersion, error) {
+	args := c.called(ctx, "SchemaUsages", id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]SubjectVersion), args.Error(1)
+}
+
+// SubjectsUsingSchema method mock, the options are ignored.
+func (c *ClientMock) SubjectsUsingSchema(ctx context.Context, id int, opts ...QueryOption) ([]string, error) {
+	args := c.called(ctx, "SubjectsUsingSchema", id)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // Subjects method mock
 func (c *ClientMock) Subjects(ctx context.Context) (subjects []string, err error) {
-	args := c.Called()
+	args := c.called(ctx, "Subjects")
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// SubjectsWithPrefix method mock
+func (c *ClientMock) SubjectsWithPrefix(ctx context.Context, prefix string) (subjects []string, err error) {
+	args := c.called(ctx, "SubjectsWithPrefix", prefix)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// SubjectsPaged method mock
+func (c *ClientMock) SubjectsPaged(ctx context.Context, offset int, limit int) (subjects []string, err error) {
+	args := c.called(ctx, "SubjectsPaged", offset, limit)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,7 +168,7 @@ func (c *ClientMock) Subjects(ctx context.Context) (subjects []string, err error
 
 // Versions method mock
 func (c *ClientMock) Versions(ctx context.Context, subject string) (versions []int, err error) {
-	args := c.Called(subject)
+	args := c.called(ctx, "Versions", subject)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -40,9 +177,9 @@ func (c *ClientMock) Versions(ctx context.Context, subject string) (versions []i
 	return args.Get(0).([]int), args.Error(1)
 }
 
-// DeleteSubject method mock
-func (c *ClientMock) DeleteSubject(ctx context.Context, subject string, permanent bool) (versions []int, err error) {
-	args := c.Called(subject, permanent)
+// DeleteSubject method mock, the options are ignored.
+func (c *ClientMock) DeleteSubject(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (versions []int, err error) {
+	args := c.called(ctx, "DeleteSubject", subject, permanent)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -51,9 +188,38 @@ func (c *ClientMock) DeleteSubject(ctx context.Context, subject string, permanen
 	return args.Get(0).([]int), args.Error(1)
 }
 
+// SubjectExists method mock
+func (c *ClientMock) SubjectExists(ctx context.Context, subject string) (bool, error) {
+	args := c.called(ctx, "SubjectExists", subject)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// DeleteSubjectIfExists method mock
+func (c *ClientMock) DeleteSubjectIfExists(ctx context.Context, subject string) (deleted bool, versions []int, err error) {
+	args := c.called(ctx, "DeleteSubjectIfExists", subject)
+
+	if args.Get(1) == nil {
+		return args.Bool(0), nil, args.Error(2)
+	}
+
+	return args.Bool(0), args.Get(1).([]int), args.Error(2)
+}
+
 // IsRegistered method mock
 func (c *ClientMock) IsRegistered(ctx context.Context, subject string, schema string) (bool, *Schema, error) {
-	args := c.Called(subject, schema)
+	args := c.called(ctx, "IsRegistered", subject, schema)
+
+	if args.Get(1) == nil {
+		return args.Bool(0), nil, args.Error(2)
+	}
+
+	return args.Bool(0), args.Get(1).(*Schema), args.Error(2)
+}
+
+// IsRegisteredIncludingDeleted method mock
+func (c *ClientMock) IsRegisteredIncludingDeleted(ctx context.Context, subject string, schema string) (bool, *Schema, error) {
+	args := c.called(ctx, "IsRegisteredIncludingDeleted", subject, schema)
 
 	if args.Get(1) == nil {
 		return args.Bool(0), nil, args.Error(2)
@@ -62,16 +228,90 @@ func (c *ClientMock) IsRegistered(ctx context.Context, subject string, schema st
 	return args.Bool(0), args.Get(1).(*Schema), args.Error(2)
 }
 
+// GetIDBySchema method mock
+func (c *ClientMock) GetIDBySchema(ctx context.Context, subject string, schema string) (int, error) {
+	args := c.called(ctx, "GetIDBySchema", subject, schema)
+
+	return args.Int(0), args.Error(1)
+}
+
+// GetVersionBySchema method mock
+func (c *ClientMock) GetVersionBySchema(ctx context.Context, subject string, schema string) (int, error) {
+	args := c.called(ctx, "GetVersionBySchema", subject, schema)
+
+	return args.Int(0), args.Error(1)
+}
+
+// Lookup method mock
+func (c *ClientMock) Lookup(ctx context.Context, subject string, schema string) (*Schema, error) {
+	args := c.called(ctx, "Lookup", subject, schema)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*Schema), args.Error(1)
+}
+
+// SchemaDiff method mock
+func (c *ClientMock) SchemaDiff(ctx context.Context, subject string, fromVersion, toVersion int) (Diff, error) {
+	args := c.called(ctx, "SchemaDiff", subject, fromVersion, toVersion)
+
+	return args.Get(0).(Diff), args.Error(1)
+}
+
+// IsLatest method mock
+func (c *ClientMock) IsLatest(ctx context.Context, subject string, schema string) (bool, error) {
+	args := c.called(ctx, "IsLatest", subject, schema)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// RegisteredVersion method mock
+func (c *ClientMock) RegisteredVersion(ctx context.Context, subject string, schema string) (int, bool, error) {
+	args := c.called(ctx, "RegisteredVersion", subject, schema)
+
+	return args.Int(0), args.Bool(1), args.Error(2)
+}
+
 // RegisterNewSchema method mock
 func (c *ClientMock) RegisterNewSchema(ctx context.Context, subject string, avroSchema string) (int, error) {
-	args := c.Called(subject, avroSchema)
+	args := c.called(ctx, "RegisterNewSchema", subject, avroSchema)
+
+	return args.Int(0), args.Error(1)
+}
+
+// RegisterNewSchemaWithID method mock
+func (c *ClientMock) RegisterNewSchemaWithID(ctx context.Context, subject string, avroSchema string, id int) (int, error) {
+	args := c.called(ctx, "RegisterNewSchemaWithID", subject, avroSchema, id)
 
 	return args.Int(0), args.Error(1)
 }
 
-// GetSchemaBySubjectAndVersion method mock
-func (c *ClientMock) GetSchemaBySubjectAndVersion(ctx context.Context, subject string, version int) (*Schema, error) {
-	args := c.Called(subject, version)
+// RegisterNewSchemaWithMetadata method mock
+func (c *ClientMock) RegisterNewSchemaWithMetadata(ctx context.Context, subject string, avroSchema string, md *Metadata, rs *RuleSet) (int, error) {
+	args := c.called(ctx, "RegisterNewSchemaWithMetadata", subject, avroSchema, md, rs)
+
+	return args.Int(0), args.Error(1)
+}
+
+// RegisterNewSchemaFromReader method mock
+func (c *ClientMock) RegisterNewSchemaFromReader(ctx context.Context, subject string, r io.Reader, schemaType string) (int, error) {
+	args := c.called(ctx, "RegisterNewSchemaFromReader", subject, r, schemaType)
+
+	return args.Int(0), args.Error(1)
+}
+
+// ImportSchema method mock
+func (c *ClientMock) ImportSchema(ctx context.Context, subject string, schema string, version int, id int) (int, error) {
+	args := c.called(ctx, "ImportSchema", subject, schema, version, id)
+
+	return args.Int(0), args.Error(1)
+}
+
+// GetSchemaBySubjectAndVersion method mock, the options are ignored.
+func (c *ClientMock) GetSchemaBySubjectAndVersion(ctx context.Context, subject string, version int, opts ...QueryOption) (*Schema, error) {
+	args := c.called(ctx, "GetSchemaBySubjectAndVersion", subject, version)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -80,9 +320,49 @@ func (c *ClientMock) GetSchemaBySubjectAndVersion(ctx context.Context, subject s
 	return args.Get(0).(*Schema), args.Error(1)
 }
 
-// GetLatestSchema method mock
-func (c *ClientMock) GetLatestSchema(ctx context.Context, subject string) (*Schema, error) {
-	args := c.Called(subject)
+// SchemaID method mock
+func (c *ClientMock) SchemaID(ctx context.Context, subject string, version int) (int, error) {
+	args := c.called(ctx, "SchemaID", subject, version)
+
+	return args.Int(0), args.Error(1)
+}
+
+// GetLatestSchema method mock, the options are ignored.
+func (c *ClientMock) GetLatestSchema(ctx context.Context, subject string, opts ...QueryOption) (*Schema, error) {
+	args := c.called(ctx, "GetLatestSchema", subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*Schema), args.Error(1)
+}
+
+// ReferencedBy method mock
+func (c *ClientMock) ReferencedBy(ctx context.Context, subject string, version int) ([]int, error) {
+	args := c.called(ctx, "ReferencedBy", subject, version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// ReferencedByLatest method mock
+func (c *ClientMock) ReferencedByLatest(ctx context.Context, subject string) ([]int, error) {
+	args := c.called(ctx, "ReferencedByLatest", subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// GetLatestSchemaMetadata method mock
+func (c *ClientMock) GetLatestSchemaMetadata(ctx context.Context, subject string) (*Schema, error) {
+	args := c.called(ctx, "GetLatestSchemaMetadata", subject)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -91,9 +371,9 @@ func (c *ClientMock) GetLatestSchema(ctx context.Context, subject string) (*Sche
 	return args.Get(0).(*Schema), args.Error(1)
 }
 
-// GetConfig method mock
-func (c *ClientMock) GetConfig(ctx context.Context, subject string) (*Config, error) {
-	args := c.Called(subject)
+// GetConfig method mock, the options are ignored.
+func (c *ClientMock) GetConfig(ctx context.Context, subject string, opts ...QueryOption) (*Config, error) {
+	args := c.called(ctx, "GetConfig", subject)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -102,30 +382,101 @@ func (c *ClientMock) GetConfig(ctx context.Context, subject string) (*Config, er
 	return args.Get(0).(*Config), args.Error(1)
 }
 
-// DeleteSchemaVersion method mock
-func (c *ClientMock) DeleteSchemaVersion(ctx context.Context, subject string, version int, permanent bool) (int, error) {
-	args := c.Called(subject, version, permanent)
+// DeleteSchemaVersion method mock, the options are ignored.
+func (c *ClientMock) DeleteSchemaVersion(ctx context.Context, subject string, version int, permanent bool, opts ...QueryOption) (int, error) {
+	args := c.called(ctx, "DeleteSchemaVersion", subject, version, permanent)
 
 	return args.Int(0), args.Error(1)
 }
 
-// DeleteLatestSchemaVersion method mock
-func (c *ClientMock) DeleteLatestSchemaVersion(ctx context.Context, subject string, permanent bool) (int, error) {
-	args := c.Called(subject, permanent)
+// DeleteLatestSchemaVersion method mock, the options are ignored.
+func (c *ClientMock) DeleteLatestSchemaVersion(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (int, error) {
+	args := c.called(ctx, "DeleteLatestSchemaVersion", subject, permanent)
 
 	return args.Int(0), args.Error(1)
 }
 
 // SchemaCompatibleWith method mock
 func (c *ClientMock) SchemaCompatibleWith(ctx context.Context, schema string, subject string, version int) (bool, error) {
-	args := c.Called(schema, subject, version)
+	args := c.called(ctx, "SchemaCompatibleWith", schema, subject, version)
 
 	return args.Bool(0), args.Error(1)
 }
 
+// SchemaCompatibleWithLatest method mock
+func (c *ClientMock) SchemaCompatibleWithLatest(ctx context.Context, schema string, subject string) (bool, error) {
+	args := c.called(ctx, "SchemaCompatibleWithLatest", schema, subject)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// CompatibilityGroup method mock, the options are ignored.
+func (c *ClientMock) CompatibilityGroup(ctx context.Context, subject string, opts ...QueryOption) (string, error) {
+	args := c.called(ctx, "CompatibilityGroup", subject)
+
+	return args.String(0), args.Error(1)
+}
+
+// EffectiveCompatibility method mock
+func (c *ClientMock) EffectiveCompatibility(ctx context.Context, subject string) (CompatibilityLevel, error) {
+	args := c.called(ctx, "EffectiveCompatibility", subject)
+
+	return args.Get(0).(CompatibilityLevel), args.Error(1)
+}
+
+// SchemaCompatibleWithFull method mock
+func (c *ClientMock) SchemaCompatibleWithFull(ctx context.Context, req CompatibilityRequest, subject string, version int) (*SchemaCompatibilityResult, error) {
+	args := c.called(ctx, "SchemaCompatibleWithFull", req, subject, version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*SchemaCompatibilityResult), args.Error(1)
+}
+
 // SetGlobalConfig method mock.
 func (c *ClientMock) SetGlobalConfig(ctx context.Context, config Config) (*Config, error) {
-	args := c.Called(config)
+	args := c.called(ctx, "SetGlobalConfig", config)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*Config), args.Error(1)
+}
+
+// TypedSchemaCompatibleWith method mock
+func (c *ClientMock) TypedSchemaCompatibleWith(ctx context.Context, schema string, schemaType string, subject string, version int) (bool, error) {
+	args := c.called(ctx, "TypedSchemaCompatibleWith", schema, schemaType, subject, version)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// SchemaCompatibleWithSubject method mock
+func (c *ClientMock) SchemaCompatibleWithSubject(ctx context.Context, schema string, subject string) (bool, error) {
+	args := c.called(ctx, "SchemaCompatibleWithSubject", schema, subject)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// CanRegister method mock
+func (c *ClientMock) CanRegister(ctx context.Context, subject string, schema string) (alreadyRegistered bool, compatible bool, err error) {
+	args := c.called(ctx, "CanRegister", subject, schema)
+
+	return args.Bool(0), args.Bool(1), args.Error(2)
+}
+
+// SetAndVerifyConfig method mock
+func (c *ClientMock) SetAndVerifyConfig(ctx context.Context, subject string, level CompatibilityLevel) error {
+	args := c.called(ctx, "SetAndVerifyConfig", subject, level)
+
+	return args.Error(0)
+}
+
+// SetConfig method mock.
+func (c *ClientMock) SetConfig(ctx context.Context, subject string, config Config) (*Config, error) {
+	args := c.called(ctx, "SetConfig", subject, config)
 
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -133,3 +484,135 @@ func (c *ClientMock) SetGlobalConfig(ctx context.Context, config Config) (*Confi
 
 	return args.Get(0).(*Config), args.Error(1)
 }
+
+// RegisterIfCompatible method mock
+func (c *ClientMock) RegisterIfCompatible(ctx context.Context, subject string, schema string) (int, error) {
+	args := c.called(ctx, "RegisterIfCompatible", subject, schema)
+
+	return args.Int(0), args.Error(1)
+}
+
+// AreCompatible method mock
+func (c *ClientMock) AreCompatible(ctx context.Context, readerSchema string, writerSchema string) (bool, error) {
+	args := c.called(ctx, "AreCompatible", readerSchema, writerSchema)
+
+	return args.Bool(0), args.Error(1)
+}
+
+// GetConfigs method mock
+func (c *ClientMock) GetConfigs(ctx context.Context, subjects []string) (map[string]*Config, error) {
+	args := c.called(ctx, "GetConfigs", subjects)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(map[string]*Config), args.Error(1)
+}
+
+// ResolveReferences method mock
+func (c *ClientMock) ResolveReferences(ctx context.Context, s *Schema) (map[string]string, error) {
+	args := c.called(ctx, "ResolveReferences", s)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+// SubjectsReferencing method mock
+func (c *ClientMock) SubjectsReferencing(ctx context.Context, subject string, version int) ([]SubjectVersion, error) {
+	args := c.called(ctx, "SubjectsReferencing", subject, version)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]SubjectVersion), args.Error(1)
+}
+
+// ImportSubjectHistory method mock
+func (c *ClientMock) ImportSubjectHistory(ctx context.Context, subject string, schemas []ImportedSchema) error {
+	args := c.called(ctx, "ImportSubjectHistory", subject, schemas)
+
+	return args.Error(0)
+}
+
+// SubjectsUsingSchemaContent method mock
+func (c *ClientMock) SubjectsUsingSchemaContent(ctx context.Context, schema string, includeDeleted bool) ([]SubjectVersion, error) {
+	args := c.called(ctx, "SubjectsUsingSchemaContent", schema, includeDeleted)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]SubjectVersion), args.Error(1)
+}
+
+// EachSubject method mock, fn is called with each subject of the first
+// returned value until it fails.
+func (c *ClientMock) EachSubject(ctx context.Context, fn func(subject string) error) error {
+	args := c.called(ctx, "EachSubject")
+
+	if args.Get(0) != nil {
+		for _, subject := range args.Get(0).([]string) {
+			err := fn(subject)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return args.Error(1)
+}
+
+// WaitForSubject method mock
+func (c *ClientMock) WaitForSubject(ctx context.Context, subject string, pollInterval time.Duration) error {
+	args := c.called(ctx, "WaitForSubject", subject, pollInterval)
+
+	return args.Error(0)
+}
+
+// SchemaCompatibilityByVersion method mock
+func (c *ClientMock) SchemaCompatibilityByVersion(ctx context.Context, schema string, subject string) (map[int]bool, error) {
+	args := c.called(ctx, "SchemaCompatibilityByVersion", schema, subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
+// DeleteAllSchemaVersions method mock
+func (c *ClientMock) DeleteAllSchemaVersions(ctx context.Context, subject string) ([]int, error) {
+	args := c.called(ctx, "DeleteAllSchemaVersions", subject)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int), args.Error(1)
+}
+
+// WaitUntilCompatible method mock
+func (c *ClientMock) WaitUntilCompatible(ctx context.Context, subject string, schema string, pollInterval time.Duration) error {
+	args := c.called(ctx, "WaitUntilCompatible", subject, schema, pollInterval)
+
+	return args.Error(0)
+}
+
+// ExportSchemas method mock
+func (c *ClientMock) ExportSchemas(ctx context.Context, w io.Writer) error {
+	args := c.called(ctx, "ExportSchemas", w)
+
+	return args.Error(0)
+}
+
+// ImportSchemas method mock
+func (c *ClientMock) ImportSchemas(ctx context.Context, r io.Reader) error {
+	args := c.called(ctx, "ImportSchemas", r)
+
+	return args.Error(0)
+}
@@ -1,11 +1,15 @@
 package schemaregistry
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
 )
 
 func Test_MockClient_GetSchemaByID(t *testing.T) {
@@ -41,6 +45,17 @@ func Test_MockClient_Subjects_with_error(t *testing.T) {
 	assert.EqualError(t, err, "some-error")
 }
 
+func Test_MockClient_SubjectsPaged(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("SubjectsPaged", 0, 2).Return([]string{"subject1", "subject2"}, nil)
+
+	subjects, err := mock.SubjectsPaged(context.Background(), 0, 2)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"subject1", "subject2"}, subjects)
+}
+
 func Test_MockClient_Versions(t *testing.T) {
 	mock := new(ClientMock)
 
@@ -235,3 +250,171 @@ func Test_MockClient_SchemaCompatibleWith(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, isCompatible)
 }
+
+func Test_MockClient_SchemaVersionsByID(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("SchemaVersionsByID", 42).Return([]SubjectVersion{{Subject: "some-subject", Version: 2}}, nil)
+
+	subjectVersions, err := mock.SchemaVersionsByID(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []SubjectVersion{{Subject: "some-subject", Version: 2}}, subjectVersions)
+}
+
+func Test_MockClient_VersionsBySchemaIDForSubject_with_error(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("VersionsBySchemaIDForSubject", 42, "some-subject").Return(nil, fmt.Errorf("some-error"))
+
+	versions, err := mock.VersionsBySchemaIDForSubject(context.Background(), 42, "some-subject")
+
+	assert.Nil(t, versions)
+	assert.EqualError(t, err, "some-error")
+}
+
+func Test_MockClient_strict_mode_with_any_context(t *testing.T) {
+	mock := NewStrictClientMock()
+
+	mock.On("GetConfig", testifymock.Anything, "some-subject").Return(&Config{Compatibility: "FULL"}, nil)
+
+	config, err := mock.GetConfig(context.Background(), "some-subject")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{Compatibility: "FULL"}, config)
+}
+
+func Test_MockClient_strict_mode_with_a_cancelled_context(t *testing.T) {
+	mock := NewStrictClientMock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	isCancelled := testifymock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Err() == context.Canceled
+	})
+
+	mock.On("GetSchemaByID", isCancelled, 42).Return("", context.Canceled)
+
+	schema, err := mock.GetSchemaByID(ctx, 42)
+
+	assert.Empty(t, schema)
+	assert.Equal(t, context.Canceled, err)
+	mock.AssertExpectations(t)
+}
+
+func Test_MockClient_SchemaDiff(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("SchemaDiff", "some-subject", 1, 2).Return(Diff{Removed: []DiffField{{Name: "id", Type: "long"}}}, nil)
+
+	diff, err := mock.SchemaDiff(context.Background(), "some-subject", 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Diff{Removed: []DiffField{{Name: "id", Type: "long"}}}, diff)
+}
+
+func Test_MockClient_CompatibilityGroup(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("CompatibilityGroup", "some-subject").Return("application.major.version", nil)
+
+	group, err := mock.CompatibilityGroup(context.Background(), "some-subject")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application.major.version", group)
+}
+
+func Test_MockClient_RegisterNewSchemaWithMetadata(t *testing.T) {
+	mock := new(ClientMock)
+	md := &Metadata{Properties: map[string]string{"owner": "team-a"}}
+
+	mock.On("RegisterNewSchemaWithMetadata", "some-subject", `"string"`, md, (*RuleSet)(nil)).Return(22, nil)
+
+	id, err := mock.RegisterNewSchemaWithMetadata(context.Background(), "some-subject", `"string"`, md, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 22, id)
+}
+
+func Test_MockClient_EachSubject(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("EachSubject").Return([]string{"a", "b", "c"}, nil)
+
+	var subjects []string
+	err := mock.EachSubject(context.Background(), func(subject string) error {
+		subjects = append(subjects, subject)
+		if subject == "b" {
+			return fmt.Errorf("some-error")
+		}
+		return nil
+	})
+
+	assert.EqualError(t, err, "some-error")
+	assert.Equal(t, []string{"a", "b"}, subjects)
+}
+
+func Test_MockClient_WaitForSubject(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("WaitForSubject", "some-subject", time.Second).Return(context.DeadlineExceeded)
+
+	err := mock.WaitForSubject(context.Background(), "some-subject", time.Second)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func Test_MockClient_SchemaCompatibilityByVersion(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("SchemaCompatibilityByVersion", `"string"`, "some-subject").Return(map[int]bool{1: true, 2: false}, nil)
+
+	compatibilities, err := mock.SchemaCompatibilityByVersion(context.Background(), `"string"`, "some-subject")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true, 2: false}, compatibilities)
+}
+
+func Test_MockClient_DeleteAllSchemaVersions(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("DeleteAllSchemaVersions", "some-subject").Return([]int{1, 2}, nil)
+
+	versions, err := mock.DeleteAllSchemaVersions(context.Background(), "some-subject")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, versions)
+}
+
+func Test_MockClient_WaitUntilCompatible(t *testing.T) {
+	mock := new(ClientMock)
+
+	mock.On("WaitUntilCompatible", "some-subject", `"string"`, time.Second).Return(nil)
+
+	err := mock.WaitUntilCompatible(context.Background(), "some-subject", `"string"`, time.Second)
+
+	assert.NoError(t, err)
+}
+
+func Test_MockClient_ExportSchemas(t *testing.T) {
+	mock := new(ClientMock)
+	var w bytes.Buffer
+
+	mock.On("ExportSchemas", &w).Return(nil)
+
+	err := mock.ExportSchemas(context.Background(), &w)
+
+	assert.NoError(t, err)
+}
+
+func Test_MockClient_ImportSchemas(t *testing.T) {
+	mock := new(ClientMock)
+	r := strings.NewReader(`{"subject": "some-subject", "version": 1, "id": 42, "schema": "\"string\""}`)
+
+	mock.On("ImportSchemas", r).Return(fmt.Errorf("import failed"))
+
+	err := mock.ImportSchemas(context.Background(), r)
+
+	assert.EqualError(t, err, "import failed")
+}
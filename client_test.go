@@ -1,10 +1,21 @@
 package schemaregistry
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,6 +30,69 @@ func Test_NewClient_with_an_invalid_baseurl(t *testing.T) {
 	assert.Nil(t, client)
 }
 
+func Test_NewClient_without_scheme(t *testing.T) {
+	for _, baseURL := range []string{"registry", "registry:8081", "registry:8081/sr", "10.0.0.1:8081", "[::1]:8081/sr"} {
+		client, err := NewClient(baseURL)
+
+		require.NoError(t, err, baseURL)
+		assert.Equal(t, "https", client.baseURL.Scheme, baseURL)
+		assert.Equal(t, "https://"+baseURL+"/", client.baseURL.String(), baseURL)
+	}
+}
+
+func Test_NewClient_without_scheme_and_a_scheme_in_the_query(t *testing.T) {
+	client, err := NewClient("registry:8081/sr?next=http://other")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https", client.baseURL.Scheme)
+	assert.Equal(t, "registry:8081", client.baseURL.Host)
+	assert.Equal(t, "/sr/", client.baseURL.Path)
+}
+
+func Test_NewClient_without_scheme_and_an_invalid_host(t *testing.T) {
+	client, err := NewClient("registry:port")
+
+	assert.EqualError(t, err, `parse "registry:port": invalid port ":port" after host`)
+	assert.Nil(t, client)
+}
+
+func Test_NewClient_with_an_unsupported_scheme(t *testing.T) {
+	client, err := NewClient("ftp://registry")
+
+	assert.EqualError(t, err, `invalid base URL ftp://registry: unsupported scheme "ftp", it must be http or https`)
+	assert.Nil(t, client)
+}
+
+func Test_NewClient_without_host(t *testing.T) {
+	client, err := NewClient("http:///sr")
+
+	assert.EqualError(t, err, "invalid base URL http:///sr: missing host")
+	assert.Nil(t, client)
+
+	client, err = NewClient("/sr")
+
+	assert.EqualError(t, err, "invalid base URL /sr: missing host")
+	assert.Nil(t, client)
+}
+
+// newClosedServerURL returns the URL of a server already closed, refusing the
+// connections.
+func newClosedServerURL() string {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+
+	return ts.URL
+}
+
+// assertNetworkError asserts the error is the failure of the request sent with
+// the method to the URL.
+func assertNetworkError(t *testing.T, err error, method string, rawURL string) {
+	var urlErr *url.Error
+	require.True(t, errors.As(err, &urlErr), err)
+	assert.Equal(t, method, urlErr.Op)
+	assert.Equal(t, rawURL, urlErr.URL)
+}
+
 func Test_NewClient_with_a_custom_client(t *testing.T) {
 	// Add a custom timeout
 	customClient := &http.Client{Timeout: time.Hour}
@@ -31,43 +105,143 @@ func Test_NewClient_with_a_custom_client(t *testing.T) {
 	assert.EqualValues(t, customClient, client.client)
 }
 
-func Test_GetSchemaByID_success(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/schemas/ids/42", r.URL.String())
+func Test_NewClient_with_a_path_prefix(t *testing.T) {
+	for _, prefix := range []string{"/sr", "/sr/"} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/sr/subjects/foo/versions", r.URL.String())
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[1]`))
+			require.NoError(t, err)
+		}))
 
+		client, err := NewClient(ts.URL + prefix)
+		require.NoError(t, err)
+
+		versions, err := client.Versions(context.Background(), "foo")
+
+		assert.NoError(t, err, prefix)
+		assert.EqualValues(t, []int{1}, versions, prefix)
+
+		ts.Close()
+	}
+}
+
+func Test_NewClient_with_a_multi_segment_path_prefix(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		expected string
+	}{
+		{"http://host", "http://host/subjects/foo/versions"},
+		{"http://host/", "http://host/subjects/foo/versions"},
+		{"http://host/api/v2", "http://host/api/v2/subjects/foo/versions"},
+		{"http://host/api/v2/", "http://host/api/v2/subjects/foo/versions"},
+		{"http://host/api%2Fv2", "http://host/api%2Fv2/subjects/foo/versions"},
+	}
+
+	for _, test := range tests {
+		client, err := NewClient(test.baseURL)
+		require.NoError(t, err)
+
+		path, err := url.Parse("subjects/foo/versions")
+		require.NoError(t, err)
+
+		assert.Equal(t, test.expected, client.baseURL.ResolveReference(path).String(), test.baseURL)
+	}
+}
+
+func Test_Client_Close(t *testing.T) {
+	closed := make(chan struct{})
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
+		_, err := w.Write([]byte(`[1]`))
 		require.NoError(t, err)
 	}))
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			close(closed)
+		}
+	}
+	ts.Start()
 	defer ts.Close()
 
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.GetSchemaByID(context.Background(), 42)
+	_, err = client.Versions(context.Background(), "test")
+	require.NoError(t, err)
 
-	assert.NoError(t, err)
-	assert.Equal(t, `{"type": "string"}`, schema)
+	client.Close()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the idle connection should be closed")
+	}
 }
 
-func Test_GetSchemaByID_with_a_network_error(t *testing.T) {
-	client, err := NewClient("foobar://unreachable-url")
+func Test_Client_Close_with_a_custom_client(t *testing.T) {
+	customClient := &http.Client{}
+
+	client, err := NewClient("some-url", UsingClient(customClient))
 	require.NoError(t, err)
 
-	schema, err := client.GetSchemaByID(context.Background(), 42)
+	assert.NotPanics(t, client.Close)
+}
 
-	assert.Empty(t, schema)
-	assert.EqualError(t, err, `Get "foobar://unreachable-url/schemas/ids/42": unsupported protocol scheme "foobar"`)
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
 }
 
-func Test_GetSchemaByID_with_a_remote_error(t *testing.T) {
+func Test_NewClient_with_defaults_timeouts(t *testing.T) {
+	var deadline time.Time
+
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		var ok bool
+		deadline, ok = req.Context().Deadline()
+		assert.True(t, ok)
+
+		return nil, fmt.Errorf("some-error")
+	})}
+
+	client, err := NewClient("http://some-url", UsingClient(httpClient), UsingDefaults(Defaults{
+		ReadTimeout:  time.Minute,
+		WriteTimeout: time.Hour,
+	}))
+	require.NoError(t, err)
+
+	_, _ = client.Versions(context.Background(), "test")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+
+	_, _ = client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, 10*time.Second)
+}
+
+func Test_NewClient_without_defaults_timeouts(t *testing.T) {
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		_, ok := req.Context().Deadline()
+		assert.False(t, ok)
+
+		return nil, fmt.Errorf("some-error")
+	})}
+
+	client, err := NewClient("http://some-url", UsingClient(httpClient))
+	require.NoError(t, err)
+
+	_, _ = client.Versions(context.Background(), "test")
+	_, _ = client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+}
+
+func Test_GetSchemaByID_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, err := w.Write([]byte(`{
-"error_code": 404,
-			"message": "schema not found"
-		}`))
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/schemas/ids/42", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -77,14 +251,16 @@ func Test_GetSchemaByID_with_a_remote_error(t *testing.T) {
 
 	schema, err := client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, schema)
-	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/schemas/ids/42) failed with error code 404: schema not found", ts.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type": "string"}`, schema)
 }
 
-func Test_GetSchemaByID_with_an_invalid_json_as_response(t *testing.T) {
+func Test_GetSchemaByID_accept_header(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.schemaregistry.v1+json, application/vnd.schemaregistry+json, application/json", r.Header.Get("Accept"))
+
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -92,43 +268,42 @@ func Test_GetSchemaByID_with_an_invalid_json_as_response(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.GetSchemaByID(context.Background(), 42)
+	_, err = client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, schema)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.NoError(t, err)
 }
 
-func Test_Subjects_success(t *testing.T) {
+func Test_GetSchemaByID_with_a_custom_accept_header(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/subjects", r.URL.String())
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`["subject1", "subject2"]`))
+		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
 
-	client, err := NewClient(ts.URL)
+	client, err := NewClient(ts.URL, UsingAccept("application/json"))
 	require.NoError(t, err)
 
-	subjects, err := client.Subjects(context.Background())
+	_, err = client.GetSchemaByID(context.Background(), 42)
 
 	assert.NoError(t, err)
-	assert.EqualValues(t, []string{"subject1", "subject2"}, subjects)
 }
 
-func Test_Subjects_with_a_network_error(t *testing.T) {
-	client, err := NewClient("foobar://unreachable-url")
+func Test_GetSchemaByID_with_a_network_error(t *testing.T) {
+	baseURL := newClosedServerURL()
+
+	client, err := NewClient(baseURL)
 	require.NoError(t, err)
 
-	schema, err := client.Subjects(context.Background())
+	schema, err := client.GetSchemaByID(context.Background(), 42)
 
 	assert.Empty(t, schema)
-	assert.EqualError(t, err, `Get "foobar://unreachable-url/subjects": unsupported protocol scheme "foobar"`)
+	assertNetworkError(t, err, "Get", baseURL+"/schemas/ids/42")
 }
 
-func Test_Subjects_with_a_remote_error(t *testing.T) {
+func Test_GetSchemaByID_with_a_remote_error(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		_, err := w.Write([]byte(`{
@@ -142,16 +317,16 @@ func Test_Subjects_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.Subjects(context.Background())
+	schema, err := client.GetSchemaByID(context.Background(), 42)
 
 	assert.Empty(t, schema)
-	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects) failed with error code 404: schema not found", ts.URL))
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/schemas/ids/42) failed with error code 404: schema not found", ts.URL))
 }
 
-func Test_Subjects_with_an_invalid_json_as_response(t *testing.T) {
+func Test_GetSchemaByID_with_a_remote_error_without_message(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, err := w.Write([]byte(`{}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -159,59 +334,164 @@ func Test_Subjects_with_an_invalid_json_as_response(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.Subjects(context.Background())
+	_, err = client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, schema)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/schemas/ids/42) failed with HTTP status 503: Service Unavailable", ts.URL))
 }
 
-func Test_Versions_success(t *testing.T) {
+func Test_GetSchemaByID_with_an_empty_body(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/subjects/foobar/versions", r.URL.String())
-
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`[1, 2, 3, 4]`))
-		require.NoError(t, err)
+		w.WriteHeader(http.StatusNoContent)
 	}))
 	defer ts.Close()
 
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.Versions(context.Background(), "foobar")
+	schema, err := client.GetSchemaByID(context.Background(), 42)
 
-	assert.NoError(t, err)
-	assert.EqualValues(t, []int{1, 2, 3, 4}, versions)
+	assert.Empty(t, schema)
+	assert.Equal(t, ErrEmptyResponse, err)
 }
 
-func Test_Versions_with_an_unparsable_subject(t *testing.T) {
-	client, err := NewClient("foobar://unreachable-url")
+func Test_GetSchemaByID_with_an_invalid_json_as_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.Versions(context.Background(), "%gh&%ij")
+	schema, err := client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, versions)
-	assert.EqualError(t, err, `parse "subjects/%gh&%ij/versions": invalid URL escape "%gh"`)
+	assert.Empty(t, schema)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
 }
 
-func Test_Versions_with_a_network_error(t *testing.T) {
-	client, err := NewClient("foobar://unreachable-url")
+func Test_GetSchemaByID_with_an_authentication_error(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected AuthError
+	}{
+		{
+			name:   "401 without body",
+			status: http.StatusUnauthorized,
+			expected: AuthError{
+				StatusCode: http.StatusUnauthorized,
+				Method:     "GET",
+			},
+		},
+		{
+			name:   "401 with a JSON body",
+			status: http.StatusUnauthorized,
+			body:   `{"error_code": 401, "message": "Unauthorized"}`,
+			expected: AuthError{
+				StatusCode: http.StatusUnauthorized,
+				Method:     "GET",
+				Message:    "Unauthorized",
+				Err: ResourceError{
+					ErrorCode:  401,
+					Method:     "GET",
+					Message:    "Unauthorized",
+					StatusCode: http.StatusUnauthorized,
+				},
+			},
+		},
+		{
+			name:   "403 without body",
+			status: http.StatusForbidden,
+			expected: AuthError{
+				StatusCode: http.StatusForbidden,
+				Method:     "GET",
+			},
+		},
+		{
+			name:   "403 with a JSON body",
+			status: http.StatusForbidden,
+			body:   `{"error_code": 40301, "message": "User is denied operation Read on Subject: test"}`,
+			expected: AuthError{
+				StatusCode: http.StatusForbidden,
+				Method:     "GET",
+				Message:    "User is denied operation Read on Subject: test",
+				Err: ResourceError{
+					ErrorCode:  40301,
+					Method:     "GET",
+					Message:    "User is denied operation Read on Subject: test",
+					StatusCode: http.StatusForbidden,
+				},
+			},
+		},
+		{
+			name:   "403 with a text body",
+			status: http.StatusForbidden,
+			body:   "Access denied\n",
+			expected: AuthError{
+				StatusCode: http.StatusForbidden,
+				Method:     "GET",
+				Message:    "Access denied",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				w.WriteHeader(tc.status)
+				_, err := w.Write([]byte(tc.body))
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 3, Delay: time.Millisecond}))
+			require.NoError(t, err)
+
+			schema, err := client.GetSchemaByID(context.Background(), 42)
+
+			assert.Empty(t, schema)
+			assert.True(t, IsAuthError(err))
+
+			expected := tc.expected
+			expected.URI = ts.URL + "/schemas/ids/42"
+			if resErr, ok := expected.Err.(ResourceError); ok {
+				resErr.URI = expected.URI
+				expected.Err = resErr
+			}
+			assert.Equal(t, expected, err)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func Test_GetSchemaByID_with_an_authorization_error_code(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, err := w.Write([]byte(`{"error_code": 40301, "message": "denied"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.Versions(context.Background(), "foobar")
+	_, err = client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, versions)
-	assert.EqualError(t, err, `Get "foobar://unreachable-url/subjects/foobar/versions": unsupported protocol scheme "foobar"`)
+	assert.True(t, HasErrorCode(err, 40301))
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/schemas/ids/42) authorization failed with HTTP status 403: denied", ts.URL))
 }
 
-func Test_Versions_with_a_remote_error(t *testing.T) {
+func Test_GetSchemaByID_with_an_html_page_as_response(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, err := w.Write([]byte(`{
-"error_code": 404,
-			"message": "subject not found"
-		}`))
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`<html><body><form action="/login"></form></body></html>`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -219,16 +499,24 @@ func Test_Versions_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	subjects, err := client.Versions(context.Background(), "foobar")
+	schema, err := client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, subjects)
-	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects/foobar/versions) failed with error code 404: subject not found", ts.URL))
+	assert.Empty(t, schema)
+
+	var contentTypeErr UnexpectedContentTypeError
+	require.True(t, errors.As(err, &contentTypeErr))
+	assert.Equal(t, UnexpectedContentTypeError{
+		ContentType: "text/html; charset=utf-8",
+		Method:      "GET",
+		URI:         ts.URL + "/schemas/ids/42",
+	}, contentTypeErr)
 }
 
-func Test_Versions_with_an_invalid_json_as_response(t *testing.T) {
+func Test_GetSchemaByID_with_an_html_page_without_content_type(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The content type is detected from the body by the server.
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		_, err := w.Write([]byte(`<!DOCTYPE html><html><body>Sign in</body></html>`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -236,19 +524,19 @@ func Test_Versions_with_an_invalid_json_as_response(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	subject, err := client.Versions(context.Background(), "foobar")
+	_, err = client.GetSchemaByID(context.Background(), 42)
 
-	assert.Empty(t, subject)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.IsType(t, UnexpectedContentTypeError{}, err)
 }
 
-func Test_DeleteSubject_success(t *testing.T) {
+func Test_GetSchemaByIDInSubject_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "DELETE", r.Method)
-		assert.Equal(t, "/subjects/foobar?permanent=true", r.URL.String())
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/schemas/ids/42?subject=%3A.ctx%3Atest", r.URL.String())
+		assert.Equal(t, ":.ctx:test", r.URL.Query().Get("subject"))
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`[1, 2, 3, 4]`))
+		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -256,19 +544,18 @@ func Test_DeleteSubject_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.DeleteSubject(context.Background(), "foobar", true)
+	schema, err := client.GetSchemaByIDInSubject(context.Background(), 42, ":.ctx:test")
 
 	assert.NoError(t, err)
-	assert.EqualValues(t, []int{1, 2, 3, 4}, versions)
+	assert.Equal(t, `{"type": "string"}`, schema)
 }
 
-func Test_DeleteSubject_with_an_error(t *testing.T) {
+func Test_GetSchemaByIDTyped_with_an_avro_schema(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, err := w.Write([]byte(`{
-"error_code": 404,
-			"message": "subject not found"
-		}`))
+		assert.Equal(t, "/schemas/ids/42", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -276,16 +563,17 @@ func Test_DeleteSubject_with_an_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+	schema, schemaType, err := client.GetSchemaByIDTyped(context.Background(), 42)
 
-	assert.Empty(t, versions)
-	assert.EqualError(t, err, fmt.Sprintf("client: (DELETE: %s/subjects/foobar?permanent=false) failed with error code 404: subject not found", ts.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, `"string"`, schema)
+	assert.Equal(t, SchemaTypeAvro, schemaType)
 }
 
-func Test_DeleteSubject_with_an_invalid_json_as_response(t *testing.T) {
+func Test_GetSchemaByIDTyped_with_a_protobuf_schema(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		_, err := w.Write([]byte(`{"schema": "message Test {}", "schemaType": "PROTOBUF"}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -293,16 +581,17 @@ func Test_DeleteSubject_with_an_invalid_json_as_response(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.DeleteSubject(context.Background(), "foobar", true)
+	schema, schemaType, err := client.GetSchemaByIDTyped(context.Background(), 42)
 
-	assert.Empty(t, versions)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.NoError(t, err)
+	assert.Equal(t, "message Test {}", schema)
+	assert.Equal(t, SchemaTypeProtobuf, schemaType)
 }
 
-func Test_DeleteSubject_with_an_invalid_json_as_error_response(t *testing.T) {
+func Test_GetSchemaByIDTyped_with_an_unknown_id(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusBadRequest)
-		_, err := w.Write([]byte(`not a valid json`))
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -310,24 +599,27 @@ func Test_DeleteSubject_with_an_invalid_json_as_error_response(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+	schema, schemaType, err := client.GetSchemaByIDTyped(context.Background(), 42)
 
-	assert.Empty(t, versions)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.True(t, IsSchemaNotFound(err))
+	assert.Empty(t, schema)
+	assert.Empty(t, schemaType)
 }
 
-func Test_IsRegistered_success(t *testing.T) {
+func Test_SchemaFromMessage_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "/subjects/test", r.URL.String())
+		var body string
+		switch r.URL.String() {
+		case "/schemas/ids/42":
+			body = `{"schema": "\"string\""}`
+		case "/schemas/ids/42/versions":
+			body = `[{"subject": "users-value", "version": 2}, {"subject": "accounts-value", "version": 5}]`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{
-		"subject": "test",
-		"id": 1,
-		"version": 3,
-		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }] }"
-	}`))
+		_, err := w.Write([]byte(body))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -335,28 +627,74 @@ func Test_IsRegistered_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	exists, schema, err := client.IsRegistered(context.Background(), "test", `{
-		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }]
-		}"
-    }`)
+	schema, payload, err := client.SchemaFromMessage(context.Background(), EncodePayload(42, []byte("payload")))
 
 	assert.NoError(t, err)
-	assert.True(t, exists)
-	assert.EqualValues(t, &Schema{
-		Subject: "test",
-		ID:      1,
-		Version: 3,
-		Schema:  `{ "type": "record", "name": "test", "fields": [{ "type": "string", "name": "field1" }] }`,
+	assert.Equal(t, &Schema{
+		Schema:     `"string"`,
+		SchemaType: SchemaTypeAvro,
+		ID:         42,
+		Subject:    "accounts-value",
+		Version:    5,
 	}, schema)
+	assert.Equal(t, []byte("payload"), payload)
 }
 
-func Test_IsRegistered_with_a_remote_error(t *testing.T) {
+func Test_SchemaFromMessage_with_failing_usages(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"unsupported endpoint", http.StatusNotFound, `{"error_code": 404, "message": "HTTP 404 Not Found"}`},
+		{"forbidden endpoint", http.StatusForbidden, ``},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.String() == "/schemas/ids/42/versions" {
+					w.WriteHeader(tc.status)
+					_, err := w.Write([]byte(tc.body))
+					require.NoError(t, err)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				_, err := w.Write([]byte(`{"schema": "\"string\""}`))
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL)
+			require.NoError(t, err)
+
+			schema, payload, err := client.SchemaFromMessage(context.Background(), EncodePayload(42, []byte("payload")))
+
+			assert.NoError(t, err)
+			assert.Equal(t, &Schema{Schema: `"string"`, SchemaType: SchemaTypeAvro, ID: 42}, schema)
+			assert.Equal(t, []byte("payload"), payload)
+		})
+	}
+}
+
+func Test_SchemaFromMessage_follows_the_usages(t *testing.T) {
+	var usagesQueries int32
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, err := w.Write([]byte(`{
-"error_code": 404,
-			"message": "schema not found"
-		}`))
+		if r.URL.String() == "/schemas/ids/42/versions" {
+			body := `[]`
+			if atomic.AddInt32(&usagesQueries, 1) > 1 {
+				body = `[{"subject": "users-value", "version": 2}]`
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(body))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -364,20 +702,33 @@ func Test_IsRegistered_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	exists, schema, err := client.IsRegistered(context.Background(), "test", `{
-		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }]
-		}"
-    }`)
+	schema, _, err := client.SchemaFromMessage(context.Background(), EncodePayload(42, []byte("payload")))
+	require.NoError(t, err)
+	assert.Empty(t, schema.Subject)
 
-	assert.Empty(t, schema)
-	assert.False(t, exists)
-	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/subjects/test) failed with error code 404: schema not found", ts.URL))
+	schema, _, err = client.SchemaFromMessage(context.Background(), EncodePayload(42, []byte("payload")))
+	require.NoError(t, err)
+	assert.Equal(t, "users-value", schema.Subject)
+	assert.Equal(t, 2, schema.Version)
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&usagesQueries))
 }
 
-func Test_IsRegistered_with_an_invalid_response_format(t *testing.T) {
+func Test_SchemaFromMessage_with_a_canceled_usages_lookup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.String() == "/schemas/ids/42/versions" {
+			cancel()
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`[]`))
+			require.NoError(t, err)
+			return
+		}
+
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte("not a valid json"))
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -385,47 +736,35 @@ func Test_IsRegistered_with_an_invalid_response_format(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	exists, schema, err := client.IsRegistered(context.Background(), "test", `{
-		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }]
-		}"
-    }`)
+	schema, payload, err := client.SchemaFromMessage(ctx, EncodePayload(42, []byte("payload")))
 
-	assert.Empty(t, schema)
-	assert.False(t, exists)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.Nil(t, schema)
+	assert.Nil(t, payload)
+	assert.True(t, errors.Is(err, context.Canceled))
 }
 
-func Test_RegisterNewSchema_success(t *testing.T) {
+func Test_SchemaFromMessage_with_a_malformed_prefix(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "/subjects/test/versions", r.URL.String())
-
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{"id": 1}`))
-		require.NoError(t, err)
+		assert.Fail(t, "the request should not be sent")
 	}))
 	defer ts.Close()
 
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	version, err := client.RegisterNewSchema(context.Background(), "test", `{
-		"type": "record",
-		"name": "test",
-		"fields": [{ "type": "string", "name": "field1" }]
-    }`)
+	schema, payload, err := client.SchemaFromMessage(context.Background(), []byte{0x01, 0x00, 0x00, 0x00, 0x2a})
+	assert.Nil(t, schema)
+	assert.Nil(t, payload)
+	assert.EqualError(t, err, "invalid message: unknown magic byte 0x1")
 
-	assert.NoError(t, err)
-	assert.Equal(t, 1, version)
+	_, _, err = client.SchemaFromMessage(context.Background(), []byte{0x00, 0x00})
+	assert.EqualError(t, err, "invalid message: expected at least 5 bytes, got 2")
 }
 
-func Test_RegisterNewSchema_with_a_remote_error(t *testing.T) {
+func Test_SchemaFromMessage_with_an_unknown_id(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
-		_, err := w.Write([]byte(`{
-"error_code": 404,
-			"message": "subject not found"
-		}`))
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -433,20 +772,20 @@ func Test_RegisterNewSchema_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	version, err := client.RegisterNewSchema(context.Background(), "test", `{
-		"type": "record",
-		"name": "test",
-		"fields": [{ "type": "string", "name": "field1" }]
-    }`)
+	schema, payload, err := client.SchemaFromMessage(context.Background(), EncodePayload(42, nil))
 
-	assert.Equal(t, -1, version)
-	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/subjects/test/versions) failed with error code 404: subject not found", ts.URL))
+	assert.Nil(t, schema)
+	assert.Nil(t, payload)
+	assert.True(t, IsSchemaNotFound(err))
 }
 
-func Test_RegisterNewSchema_with_an_invalid_response_format(t *testing.T) {
+func Test_GetSchemaByGUID_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/schemas/guids/2a1b5c0e-3f4d-4b6a-9c8e-7d6f5e4a3b2c", r.URL.String())
+
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte("not a valid json"))
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -454,27 +793,36 @@ func Test_RegisterNewSchema_with_an_invalid_response_format(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	version, err := client.RegisterNewSchema(context.Background(), "test", `{
-		"type": "record",
-		"name": "test",
-		"fields": [{ "type": "string", "name": "field1" }]
-    }`)
+	schema, err := client.GetSchemaByGUID(context.Background(), "2a1b5c0e-3f4d-4b6a-9c8e-7d6f5e4a3b2c")
 
-	assert.Equal(t, -1, version)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.NoError(t, err)
+	assert.Equal(t, `"string"`, schema)
 }
 
-func Test_GetSchemabySubjectAndVersion_success(t *testing.T) {
+func Test_GetLatestSchema_with_a_guid(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/subjects/test/versions/1", r.URL.String())
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "guid": "2a1b5c0e", "schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingStrictDecoding())
+	require.NoError(t, err)
+
+	schema, err := client.GetLatestSchema(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "2a1b5c0e", schema.GUID)
 
+	raw, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"guid":"2a1b5c0e"`)
+}
+
+func Test_GetLatestSchema_without_guid(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{
-			"subject": "test",
-			"version": 1,
-			"schema": "{\"type\": \"string\"}"
-		}`))
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "schema": "\"string\""}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -482,27 +830,22 @@ func Test_GetSchemabySubjectAndVersion_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1)
+	schema, err := client.GetLatestSchema(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Empty(t, schema.GUID)
 
-	assert.NoError(t, err)
-	assert.EqualValues(t, &Schema{
-		Subject: "test",
-		Version: 1,
-		Schema:  `{"type": "string"}`,
-	}, schema)
+	raw, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "guid")
 }
 
-func Test_GetLatestSchema_success(t *testing.T) {
+func Test_SchemaVersionsByID_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/subjects/test/versions/latest", r.URL.String())
+		assert.Equal(t, "/schemas/ids/42/versions", r.URL.String())
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{
-			"subject": "test",
-			"version": 1,
-			"schema": "{\"type\": \"string\"}"
-		}`))
+		_, err := w.Write([]byte(`[{"subject": "foo", "version": 1}, {"subject": "bar", "version": 3}]`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -510,23 +853,26 @@ func Test_GetLatestSchema_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.GetLatestSchema(context.Background(), "test")
+	subjectVersions, err := client.SchemaVersionsByID(context.Background(), 42)
 
 	assert.NoError(t, err)
-	assert.EqualValues(t, &Schema{
-		Subject: "test",
-		Version: 1,
-		Schema:  `{"type": "string"}`,
-	}, schema)
+	assert.EqualValues(t, []SubjectVersion{
+		{Subject: "foo", Version: 1},
+		{Subject: "bar", Version: 3},
+	}, subjectVersions)
 }
 
-func Test_GetSchemabySubjectAndVersion_with_a_remote_error(t *testing.T) {
+func Test_LatestVersionUsingSchema_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		_, err := w.Write([]byte(`{
-			"error_code": 500,
-			"message": "internal server error"
-		}`))
+		assert.Equal(t, "/schemas/ids/42/versions", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[
+			{"subject": "foo", "version": 1},
+			{"subject": "foo", "version": 5},
+			{"subject": "bar", "version": 7},
+			{"subject": "foo", "version": 3}
+		]`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -534,16 +880,31 @@ func Test_GetSchemabySubjectAndVersion_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1)
+	version, err := client.LatestVersionUsingSchema(context.Background(), 42, "foo")
 
-	assert.Nil(t, schema)
-	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects/test/versions/1) failed with error code 500: internal server error", ts.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, version)
+
+	version, err = client.LatestVersionUsingSchema(context.Background(), 42, "baz")
+
+	assert.Equal(t, -1, version)
+	assert.True(t, IsVersionNotFound(err))
 }
 
-func Test_GetSchemabySubjectAndVersion_with_an_invalid_response_format(t *testing.T) {
+func Test_SchemaUsages_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/schemas/ids/42/versions", r.URL.Path)
+
+		versions := `[
+			{"subject": "foo", "version": 4},
+			{"subject": "bar", "version": 3},
+			{"subject": "foo", "version": 1}`
+		if r.URL.Query().Get("deleted") == "true" {
+			versions += `, {"subject": "baz", "version": 2}`
+		}
+
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		_, err := w.Write([]byte(versions + "]"))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -551,19 +912,40 @@ func Test_GetSchemabySubjectAndVersion_with_an_invalid_response_format(t *testin
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1)
+	subjectVersions, err := client.SchemaUsages(context.Background(), 42)
 
-	assert.Nil(t, schema)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []SubjectVersion{
+		{Subject: "bar", Version: 3},
+		{Subject: "foo", Version: 1},
+		{Subject: "foo", Version: 4},
+	}, subjectVersions)
+
+	subjectVersions, err = client.SchemaUsages(context.Background(), 42, WithDeleted())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []SubjectVersion{
+		{Subject: "bar", Version: 3},
+		{Subject: "baz", Version: 2},
+		{Subject: "foo", Version: 1},
+		{Subject: "foo", Version: 4},
+	}, subjectVersions)
 }
 
-func Test_GetConfig_success(t *testing.T) {
+func Test_SubjectsUsingSchema_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "GET", r.Method)
-		assert.Equal(t, "/config/test", r.URL.String())
+		assert.Equal(t, "/schemas/ids/42/versions", r.URL.Path)
+
+		versions := `[
+			{"subject": "foo", "version": 4},
+			{"subject": "bar", "version": 3},
+			{"subject": "foo", "version": 1}`
+		if r.URL.Query().Get("deleted") == "true" {
+			versions += `, {"subject": "baz", "version": 2}`
+		}
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{"compatibility": "FULL"}`))
+		_, err := w.Write([]byte(versions + "]"))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -571,21 +953,21 @@ func Test_GetConfig_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	config, err := client.GetConfig(context.Background(), "test")
+	subjects, err := client.SubjectsUsingSchema(context.Background(), 42)
 
 	assert.NoError(t, err)
-	assert.EqualValues(t, &Config{
-		Compatibility: "FULL",
-	}, config)
+	assert.EqualValues(t, []string{"bar", "foo"}, subjects)
+
+	subjects, err = client.SubjectsUsingSchema(context.Background(), 42, WithDeleted())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"bar", "baz", "foo"}, subjects)
 }
 
-func Test_GetConfig_with_a_remote_error(t *testing.T) {
+func Test_SubjectsUsingSchema_with_an_unknown_id(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		_, err := w.Write([]byte(`{
-			"error_code": 500,
-			"message": "internal server error"
-		}`))
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -593,16 +975,33 @@ func Test_GetConfig_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	config, err := client.GetConfig(context.Background(), "test")
+	subjects, err := client.SubjectsUsingSchema(context.Background(), 42)
 
-	assert.Nil(t, config)
-	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/config/test) failed with error code 500: internal server error", ts.URL))
+	assert.Nil(t, subjects)
+	assert.True(t, IsSchemaNotFound(err))
 }
 
-func Test_GetConfig_with_an_invalid_response_format(t *testing.T) {
+func Test_SubjectsUsingSchemaContent_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/subjects":
+			status, body = http.StatusOK, `["users", "accounts", "orders"]`
+		case "/subjects/users":
+			status, body = http.StatusOK, `{"subject": "users", "id": 10, "version": 1, "schema": "\"string\""}`
+		case "/subjects/accounts":
+			status, body = http.StatusOK, `{"subject": "accounts", "id": 10, "version": 3, "schema": "\"string\""}`
+		case "/subjects/orders":
+			status, body = http.StatusNotFound, `{"error_code": 40403, "message": "Schema not found"}`
+		case "/schemas/ids/10/versions":
+			status, body = http.StatusOK, `[{"subject": "users", "version": 1}, {"subject": "accounts", "version": 3}]`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -610,19 +1009,38 @@ func Test_GetConfig_with_an_invalid_response_format(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	config, err := client.GetConfig(context.Background(), "test")
+	subjectVersions, err := client.SubjectsUsingSchemaContent(context.Background(), `"string"`, false)
 
-	assert.Nil(t, config)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.NoError(t, err)
+	assert.Equal(t, []SubjectVersion{
+		{Subject: "accounts", Version: 3},
+		{Subject: "users", Version: 1},
+	}, subjectVersions)
 }
 
-func Test_DeleteSchemaVersion_success(t *testing.T) {
+func Test_SubjectsUsingSchemaContent_including_deleted(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "DELETE", r.Method)
-		assert.Equal(t, "/subjects/test/versions/2?permanent=true", r.URL.String())
-
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`4`))
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/subjects?deleted=true":
+			status, body = http.StatusOK, `["users", "accounts", "orders", "legacy"]`
+		case "/subjects/users?deleted=true":
+			status, body = http.StatusOK, `{"subject": "users", "id": 10, "version": 1, "schema": "\"string\""}`
+		case "/subjects/accounts?deleted=true":
+			status, body = http.StatusOK, `{"subject": "accounts", "id": 10, "version": 3, "schema": "\"string\""}`
+		case "/subjects/legacy?deleted=true":
+			status, body = http.StatusOK, `{"subject": "legacy", "id": 10, "version": 1, "schema": "\"string\""}`
+		case "/subjects/orders?deleted=true":
+			status, body = http.StatusNotFound, `{"error_code": 40403, "message": "Schema not found"}`
+		case "/schemas/ids/10/versions?deleted=true":
+			status, body = http.StatusOK, `[{"subject": "users", "version": 1}, {"subject": "accounts", "version": 3}, {"subject": "accounts", "version": 1}, {"subject": "legacy", "version": 1}]`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -630,19 +1048,28 @@ func Test_DeleteSchemaVersion_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, true)
+	subjectVersions, err := client.SubjectsUsingSchemaContent(context.Background(), `"string"`, true)
 
 	assert.NoError(t, err)
-	assert.Equal(t, 4, id)
+	assert.Equal(t, []SubjectVersion{
+		{Subject: "accounts", Version: 1},
+		{Subject: "accounts", Version: 3},
+		{Subject: "legacy", Version: 1},
+		{Subject: "users", Version: 1},
+	}, subjectVersions)
 }
 
-func Test_DeleteSchemaVersion_with_a_remote_error(t *testing.T) {
+func Test_SubjectsUsingSchemaContent_with_an_unregistered_schema(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		_, err := w.Write([]byte(`{
-			"error_code": 500,
-			"message": "internal server error"
-		}`))
+		if r.URL.String() == "/subjects" {
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`["users"]`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -650,16 +1077,22 @@ func Test_DeleteSchemaVersion_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, false)
+	subjectVersions, err := client.SubjectsUsingSchemaContent(context.Background(), `"string"`, false)
 
-	assert.Equal(t, -1, id)
-	assert.EqualError(t, err, fmt.Sprintf("client: (DELETE: %s/subjects/test/versions/2?permanent=false) failed with error code 500: internal server error", ts.URL))
+	assert.NoError(t, err)
+	assert.Empty(t, subjectVersions)
 }
 
-func Test_DeleteSchemaVersion_with_an_invalid_response_format(t *testing.T) {
+func Test_VersionsBySchemaIDForSubject_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/schemas/ids/42/versions", r.URL.String())
+
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`not a valid json`))
+		_, err := w.Write([]byte(`[
+			{"subject": "foo", "version": 1},
+			{"subject": "bar", "version": 3},
+			{"subject": "foo", "version": 4}
+		]`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -667,19 +1100,21 @@ func Test_DeleteSchemaVersion_with_an_invalid_response_format(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, false)
+	versions, err := client.VersionsBySchemaIDForSubject(context.Background(), 42, "foo")
 
-	assert.Equal(t, -1, id)
-	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 4}, versions)
+
+	versions, err = client.VersionsBySchemaIDForSubject(context.Background(), 42, "baz")
+
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
 }
 
-func Test_DeleteLatestSchemaVersion_success(t *testing.T) {
+func Test_VersionsBySchemaIDForSubject_with_an_unknown_id(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "DELETE", r.Method)
-		assert.Equal(t, "/subjects/test/versions/latest?permanent=true", r.URL.String())
-
-		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`4`))
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -687,21 +1122,19 @@ func Test_DeleteLatestSchemaVersion_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	id, err := client.DeleteLatestSchemaVersion(context.Background(), "test", true)
+	versions, err := client.VersionsBySchemaIDForSubject(context.Background(), 42, "foo")
 
-	assert.NoError(t, err)
-	assert.Equal(t, 4, id)
+	assert.Nil(t, versions)
+	assert.True(t, IsSchemaNotFound(err))
 }
 
-func Test_SchemaCompatibleWith_success(t *testing.T) {
+func Test_Subjects_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "/compatibility/subjects/test/versions/4", r.URL.String())
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects", r.URL.String())
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{
-			"is_compatible": true
-		}`))
+		_, err := w.Write([]byte(`["subject1", "subject2"]`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -709,18 +1142,30 @@ func Test_SchemaCompatibleWith_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", 4)
+	subjects, err := client.Subjects(context.Background())
 
 	assert.NoError(t, err)
-	assert.True(t, isCompatible)
+	assert.EqualValues(t, []string{"subject1", "subject2"}, subjects)
 }
 
-func Test_SchemaCompatibleWith_with_a_remote_error(t *testing.T) {
+func Test_Subjects_with_a_network_error(t *testing.T) {
+	baseURL := newClosedServerURL()
+
+	client, err := NewClient(baseURL)
+	require.NoError(t, err)
+
+	schema, err := client.Subjects(context.Background())
+
+	assert.Empty(t, schema)
+	assertNetworkError(t, err, "Get", baseURL+"/subjects")
+}
+
+func Test_Subjects_with_a_remote_error(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.WriteHeader(http.StatusNotFound)
 		_, err := w.Write([]byte(`{
-			"error_code": 500,
-			"message": "internal server error"
+"error_code": 404,
+			"message": "schema not found"
 		}`))
 		require.NoError(t, err)
 	}))
@@ -729,13 +1174,13 @@ func Test_SchemaCompatibleWith_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", 2)
+	schema, err := client.Subjects(context.Background())
 
-	assert.False(t, isCompatible)
-	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/compatibility/subjects/test/versions/2) failed with error code 500: internal server error", ts.URL))
+	assert.Empty(t, schema)
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects) failed with error code 404: schema not found", ts.URL))
 }
 
-func Test_SchemaCompatibleWith_with_an_invalid_response_format(t *testing.T) {
+func Test_Subjects_with_an_invalid_json_as_response(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, err := w.Write([]byte(`not a valid json`))
@@ -746,19 +1191,20 @@ func Test_SchemaCompatibleWith_with_an_invalid_response_format(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", 2)
+	schema, err := client.Subjects(context.Background())
 
-	assert.False(t, isCompatible)
+	assert.Empty(t, schema)
 	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
 }
 
-func Test_SetGlobalConfig_success(t *testing.T) {
+func Test_SubjectsWithPrefix_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, "PUT", r.Method)
-		assert.Equal(t, "/config", r.URL.String())
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects?subjectPrefix=team+a%2Forders%26", r.URL.String())
+		assert.Equal(t, "team a/orders&", r.URL.Query().Get("subjectPrefix"))
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write([]byte(`{"compatibility": "FULL"}`))
+		_, err := w.Write([]byte(`["team a/orders&-key", "team a/orders&-value"]`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -766,23 +1212,19 @@ func Test_SetGlobalConfig_success(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	config, err := client.SetGlobalConfig(context.Background(), Config{
-		Compatibility: "FULL",
-	})
+	subjects, err := client.SubjectsWithPrefix(context.Background(), "team a/orders&")
 
 	assert.NoError(t, err)
-	assert.EqualValues(t, &Config{
-		Compatibility: "FULL",
-	}, config)
+	assert.EqualValues(t, []string{"team a/orders&-key", "team a/orders&-value"}, subjects)
 }
 
-func Test_SetGlobalConfig_with_a_remote_error(t *testing.T) {
+func Test_SubjectsPaged_success(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		_, err := w.Write([]byte(`{
-			"error_code": 500,
-			"message": "internal server error"
-		}`))
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects?offset=10&limit=2", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["subject1", "subject2"]`))
 		require.NoError(t, err)
 	}))
 	defer ts.Close()
@@ -790,10 +1232,3702 @@ func Test_SetGlobalConfig_with_a_remote_error(t *testing.T) {
 	client, err := NewClient(ts.URL)
 	require.NoError(t, err)
 
-	config, err := client.SetGlobalConfig(context.Background(), Config{
-		Compatibility: "FULL",
-	})
+	subjects, err := client.SubjectsPaged(context.Background(), 10, 2)
 
-	assert.Nil(t, config)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"subject1", "subject2"}, subjects)
+}
+
+func Test_SubjectsPaged_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"error_code": 500, "message": "internal server error"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	subjects, err := client.SubjectsPaged(context.Background(), 0, 2)
+
+	assert.Empty(t, subjects)
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects?offset=0&limit=2) failed with error code 500: internal server error", ts.URL))
+}
+
+func Test_EachSubject_with_two_pages(t *testing.T) {
+	defer func(size int) { subjectsPageSize = size }(subjectsPageSize)
+	subjectsPageSize = 2
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.String() {
+		case "/subjects?offset=0&limit=2":
+			body = `["subject1", "subject2"]`
+		case "/subjects?offset=2&limit=2":
+			body = `["subject3"]`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var subjects []string
+	err = client.EachSubject(context.Background(), func(subject string) error {
+		subjects = append(subjects, subject)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"subject1", "subject2", "subject3"}, subjects)
+}
+
+func Test_EachSubject_with_a_server_ignoring_the_pagination(t *testing.T) {
+	defer func(size int) { subjectsPageSize = size }(subjectsPageSize)
+	subjectsPageSize = 2
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["subject1", "subject2"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var subjects []string
+	err = client.EachSubject(context.Background(), func(subject string) error {
+		subjects = append(subjects, subject)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"subject1", "subject2"}, subjects)
+}
+
+func Test_EachSubject_with_an_error_from_the_callback(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["subject1", "subject2"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	calls := 0
+	err = client.EachSubject(context.Background(), func(subject string) error {
+		calls++
+		return fmt.Errorf("some-error")
+	})
+
+	assert.EqualError(t, err, "some-error")
+	assert.Equal(t, 1, calls)
+}
+
+func Test_Versions_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/foobar/versions", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2, 3, 4]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "foobar")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2, 3, 4}, versions)
+}
+
+func Test_Versions_with_an_escaped_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/%25gh&%25ij/versions", r.URL.EscapedPath())
+		assert.Equal(t, "/subjects/%gh&%ij/versions", r.URL.Path)
+
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "%gh&%ij")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1}, versions)
+}
+
+func Test_Versions_with_a_context_qualified_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/:.staging:foobar/versions", r.URL.EscapedPath())
+
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), ":.staging:foobar")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2}, versions)
+}
+
+func Test_Versions_with_a_network_error(t *testing.T) {
+	baseURL := newClosedServerURL()
+
+	client, err := NewClient(baseURL)
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "foobar")
+
+	assert.Empty(t, versions)
+	assertNetworkError(t, err, "Get", baseURL+"/subjects/foobar/versions")
+}
+
+func Test_Versions_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{
+"error_code": 404,
+			"message": "subject not found"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	subjects, err := client.Versions(context.Background(), "foobar")
+
+	assert.Empty(t, subjects)
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects/foobar/versions) failed with error code 404: subject not found", ts.URL))
+}
+
+func Test_Versions_with_an_invalid_json_as_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	subject, err := client.Versions(context.Background(), "foobar")
+
+	assert.Empty(t, subject)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_DeleteSubject_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/subjects/foobar?permanent=true", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2, 3, 4]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", true)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2, 3, 4}, versions)
+}
+
+func Test_DeleteSubject_with_a_context_qualified_subject(t *testing.T) {
+	tests := []struct {
+		name        string
+		subject     string
+		escapedPath string
+	}{
+		{"default context", ":.:foobar", "/subjects/:.:foobar"},
+		{"named context", ":.staging:foobar", "/subjects/:.staging:foobar"},
+		{"dotted context", ":.eu.staging:foo.bar-value", "/subjects/:.eu.staging:foo.bar-value"},
+		{"subject with a slash", ":.staging:foo/bar", "/subjects/:.staging:foo%2Fbar"},
+		{"subject with a space", ":.staging:foo bar", "/subjects/:.staging:foo%20bar"},
+		{"subject with a question mark", ":.staging:foo?bar", "/subjects/:.staging:foo%3Fbar"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "DELETE", r.Method)
+				assert.Equal(t, tc.escapedPath, r.URL.EscapedPath())
+				assert.Equal(t, tc.subject, strings.TrimPrefix(r.URL.Path, "/subjects/"))
+				assert.Equal(t, "permanent=false", r.URL.RawQuery)
+
+				_, err := w.Write([]byte(`[1, 2]`))
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL)
+			require.NoError(t, err)
+
+			versions, err := client.DeleteSubject(context.Background(), tc.subject, false)
+
+			assert.NoError(t, err)
+			assert.EqualValues(t, []int{1, 2}, versions)
+		})
+	}
+}
+
+func Test_Client_with_a_context_qualified_subject(t *testing.T) {
+	subject := ":.staging:foo/bar"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/subjects/:.staging:foo%2Fbar/versions/3":
+			_, err := w.Write([]byte(`{"subject": ":.staging:foo/bar", "version": 3, "id": 7, "schema": "\"string\""}`))
+			require.NoError(t, err)
+		case "/subjects/:.staging:foo%2Fbar/versions":
+			_, err := w.Write([]byte(`{"id": 7}`))
+			require.NoError(t, err)
+		case "/config/:.staging:foo%2Fbar":
+			_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+			require.NoError(t, err)
+		case "/compatibility/subjects/:.staging:foo%2Fbar/versions/latest":
+			_, err := w.Write([]byte(`{"is_compatible": true}`))
+			require.NoError(t, err)
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), subject, 3)
+	require.NoError(t, err)
+	assert.Equal(t, subject, schema.Subject)
+
+	id, err := client.RegisterNewSchema(context.Background(), subject, `"string"`)
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+
+	config, err := client.GetConfig(context.Background(), subject)
+	require.NoError(t, err)
+	assert.EqualValues(t, "FULL", config.Compatibility)
+
+	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `"string"`, subject, LatestVersion)
+	require.NoError(t, err)
+	assert.True(t, isCompatible)
+}
+
+func Test_escapeSubject(t *testing.T) {
+	assert.Equal(t, "foobar", escapeSubject("foobar"))
+	assert.Equal(t, ":.staging:foobar", escapeSubject(":.staging:foobar"))
+	assert.Equal(t, ":.staging:foo%2Fbar%25", escapeSubject(":.staging:foo/bar%"))
+	assert.Equal(t, "", escapeSubject(""))
+}
+
+func Test_DeleteSubject_with_a_no_content_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", true)
+
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
+}
+
+func Test_DeleteSubject_with_an_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{
+"error_code": 404,
+			"message": "subject not found"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+
+	assert.Empty(t, versions)
+	assert.EqualError(t, err, fmt.Sprintf("client: (DELETE: %s/subjects/foobar?permanent=false) failed with error code 404: subject not found", ts.URL))
+}
+
+func Test_SubjectExists_with_an_existing_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, err := client.SubjectExists(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func Test_SubjectExists_with_a_missing_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, err := client.SubjectExists(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func Test_SubjectExists_with_an_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, err := client.SubjectExists(context.Background(), "test")
+
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+	assert.False(t, exists)
+}
+
+func Test_SubjectExists_with_a_network_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, err := client.SubjectExists(context.Background(), "test")
+
+	assert.Error(t, err)
+	assert.False(t, exists)
+}
+
+func Test_DeleteSubjectIfExists_with_an_existing_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/subjects/test?permanent=false", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	deleted, versions, err := client.DeleteSubjectIfExists(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, []int{1, 2}, versions)
+}
+
+func Test_DeleteSubjectIfExists_with_a_missing_subject(t *testing.T) {
+	for _, code := range []int{SubjectNotFound, SubjectSoftDeleted} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(fmt.Sprintf(`{"error_code": %d, "message": "subject not found"}`, code)))
+			require.NoError(t, err)
+		}))
+
+		client, err := NewClient(ts.URL)
+		require.NoError(t, err)
+
+		deleted, versions, err := client.DeleteSubjectIfExists(context.Background(), "test")
+
+		assert.NoError(t, err, code)
+		assert.False(t, deleted, code)
+		assert.Nil(t, versions, code)
+
+		ts.Close()
+	}
+}
+
+func Test_DeleteSubjectIfExists_with_an_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	deleted, versions, err := client.DeleteSubjectIfExists(context.Background(), "test")
+
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+	assert.False(t, deleted)
+	assert.Nil(t, versions)
+}
+
+func Test_DeleteSubject_with_an_invalid_json_as_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", true)
+
+	assert.Empty(t, versions)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+// The transport doesn't decompress the responses it didn't request compressed,
+// as it happens with a proxy compressing all its pages.
+var uncompressedClient = &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+func Test_DeleteSubject_with_a_gzip_error_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(`{"error_code": 50001, "message": "Error in the backend data store"}`))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err = w.Write(buf.Bytes())
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingClient(uncompressedClient))
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+
+	assert.Empty(t, versions)
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+}
+
+func Test_DeleteSubject_with_a_gzip_error_page_as_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte("<html><body>502 Bad Gateway</body></html>\n"))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err = w.Write(buf.Bytes())
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingClient(uncompressedClient))
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+
+	assert.Empty(t, versions)
+	assert.EqualError(t, err, `failed to decode the response: invalid character '<' looking for beginning of value (status 500: "<html><body>502 Bad Gateway</body></html>")`)
+}
+
+func Test_DeleteSubject_with_a_gzip_error_response_over_the_limit(t *testing.T) {
+	// The 1MB body compresses to about 1KB, under the limit of the compressed
+	// bytes read from the connection.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(`{"message": "` + strings.Repeat(" ", 1<<20) + `"}`))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err = w.Write(buf.Bytes())
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingClient(uncompressedClient), UsingMaxResponseBytes(4096))
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+
+	assert.Empty(t, versions)
+	assert.EqualError(t, err, "the response body exceeds the limit of 4096 bytes")
+}
+
+func Test_DeleteSubject_with_a_gzip_error_response_at_the_maximum_limit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(`{"error_code": 40401, "message": "Subject 'foobar' not found."}`))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusNotFound)
+		_, err = w.Write(buf.Bytes())
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingClient(uncompressedClient), UsingMaxResponseBytes(math.MaxInt64))
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+
+	assert.Empty(t, versions)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_DeleteSubject_with_an_invalid_json_as_error_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	versions, err := client.DeleteSubject(context.Background(), "foobar", false)
+
+	assert.Empty(t, versions)
+	assert.EqualError(t, err, `failed to decode the response: invalid character 'o' in literal null (expecting 'u') (status 400: "not a valid json")`)
+}
+
+func Test_IsRegistered_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+		"subject": "test",
+		"id": 1,
+		"version": 3,
+		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }] }"
+	}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, schema, err := client.IsRegistered(context.Background(), "test", `{
+		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }]
+		}"
+    }`)
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.EqualValues(t, &Schema{
+		Subject: "test",
+		ID:      1,
+		Version: 3,
+		Schema:  `{ "type": "record", "name": "test", "fields": [{ "type": "string", "name": "field1" }] }`,
+	}, schema)
+}
+
+func Test_IsRegistered_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{
+"error_code": 404,
+			"message": "schema not found"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, schema, err := client.IsRegistered(context.Background(), "test", `{
+		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }]
+		}"
+    }`)
+
+	assert.Empty(t, schema)
+	assert.False(t, exists)
+	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/subjects/test) failed with error code 404: schema not found", ts.URL))
+}
+
+func Test_IsRegistered_with_a_missing_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "Subject 'test' not found."}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, schema, err := client.IsRegistered(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Nil(t, schema)
+	assert.False(t, exists)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_IsRegistered_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte("not a valid json"))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, schema, err := client.IsRegistered(context.Background(), "test", `{
+		"schema": "{ \"type\": \"record\", \"name\": \"test\", \"fields\": [{ \"type\": \"string\", \"name\": \"field1\" }]
+		}"
+    }`)
+
+	assert.Empty(t, schema)
+	assert.False(t, exists)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_IsRegisteredIncludingDeleted_with_a_soft_deleted_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		if r.URL.Query().Get("deleted") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 12, "version": 2, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	exists, schema, err := client.IsRegistered(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.Nil(t, schema)
+
+	exists, schema, err = client.IsRegisteredIncludingDeleted(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.EqualValues(t, &Schema{
+		Subject: "test",
+		ID:      12,
+		Version: 2,
+		Schema:  `{"type": "string"}`,
+	}, schema)
+}
+
+func Test_GetIDBySchema_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.GetIDBySchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func Test_GetIDBySchema_with_an_unregistered_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.GetIDBySchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, id)
+	assert.True(t, IsSchemaNotFound(err))
+}
+
+func Test_GetVersionBySchema_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, err := client.GetVersionBySchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, version)
+}
+
+func Test_GetVersionBySchema_with_an_unregistered_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, err := client.GetVersionBySchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, version)
+	assert.True(t, IsSchemaNotFound(err))
+}
+
+func Test_Lookup_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.Lookup(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Schema{Subject: "test", ID: 42, Version: 3, Schema: `{"type": "string"}`}, schema)
+}
+
+func Test_Lookup_with_an_unregistered_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.Lookup(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Nil(t, schema)
+	assert.True(t, IsSchemaNotFound(err))
+}
+
+func Test_RegisteredVersion_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, ok, err := client.RegisteredVersion(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, version)
+}
+
+func Test_RegisteredVersion_with_an_unregistered_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, ok, err := client.RegisteredVersion(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, version)
+}
+
+func Test_RegisteredVersion_with_a_missing_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "Subject 'test' not found."}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, ok, err := client.RegisteredVersion(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, version)
+}
+
+func Test_RegisteredVersion_with_an_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{"error_code": 42201, "message": "invalid schema"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, ok, err := client.RegisteredVersion(context.Background(), "test", `{"type": "string"}`)
+
+	assert.False(t, ok)
+	assert.True(t, IsInvalidSchema(err))
+}
+
+func Test_RegisterNewSchemaWithID_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "\"string\"", "id": 42}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 42}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchemaWithID(context.Background(), "test", `"string"`, 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func Test_RegisterNewSchemaWithMetadata_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"schema": "\"string\"",
+			"metadata": {
+				"tags": {"email": ["PII"]},
+				"properties": {"owner": "team-a", "application.major.version": "2"},
+				"sensitive": ["owner"]
+			},
+			"ruleSet": {
+				"migrationRules": [{"name": "upgrade", "kind": "TRANSFORM", "mode": "UPGRADE", "type": "JSONATA", "expr": "$merge([$, {'full_name': $.name}])"}],
+				"domainRules": [{"name": "checkEmail", "kind": "CONDITION", "mode": "WRITE", "type": "CEL", "expr": "message.email != ''", "onFailure": "DLQ", "params": {"dlq.topic": "bad-users"}}]
+			}
+		}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 12}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchemaWithMetadata(context.Background(), "test", `"string"`,
+		&Metadata{
+			Tags:       map[string][]string{"email": {"PII"}},
+			Properties: map[string]string{"owner": "team-a", "application.major.version": "2"},
+			Sensitive:  []string{"owner"},
+		},
+		&RuleSet{
+			MigrationRules: []Rule{{Name: "upgrade", Kind: "TRANSFORM", Mode: "UPGRADE", Type: "JSONATA", Expr: "$merge([$, {'full_name': $.name}])"}},
+			DomainRules:    []Rule{{Name: "checkEmail", Kind: "CONDITION", Mode: "WRITE", Type: "CEL", Expr: "message.email != ''", OnFailure: "DLQ", Params: map[string]string{"dlq.topic": "bad-users"}}},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, id)
+}
+
+func Test_RegisterNewSchemaWithMetadata_without_metadata_nor_rule_set(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "\"string\""}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchemaWithMetadata(context.Background(), "test", `"string"`, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func Test_GetSchemaBySubjectAndVersion_with_metadata_and_rule_set(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"subject": "test",
+			"version": 2,
+			"id": 12,
+			"schema": "\"string\"",
+			"metadata": {"properties": {"owner": "team-a"}},
+			"ruleSet": {"domainRules": [{"name": "checkEmail", "kind": "CONDITION", "mode": "WRITE", "type": "CEL", "expr": "message.email != ''", "disabled": true}]}
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingStrictDecoding())
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, &Metadata{Properties: map[string]string{"owner": "team-a"}}, schema.Metadata)
+	assert.Equal(t, &RuleSet{
+		DomainRules: []Rule{{Name: "checkEmail", Kind: "CONDITION", Mode: "WRITE", Type: "CEL", Expr: "message.email != ''", Disabled: true}},
+	}, schema.RuleSet)
+}
+
+func Test_Schema_without_metadata_nor_rule_set_is_encoded_as_before(t *testing.T) {
+	raw, err := json.Marshal(Schema{Schema: `"string"`, Subject: "test", Version: 1})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"schema": "\"string\"", "subject": "test", "version": 1}`, string(raw))
+}
+
+func Test_RegisterNewSchemaWithID_without_id(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "\"string\""}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchemaWithID(context.Background(), "test", `"string"`, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func Test_RegisterNewSchemaFromReader_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "syntax = \"proto3\";\nmessage User { string name = 1; }\n", "schemaType": "PROTOBUF"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 7}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema := "syntax = \"proto3\";\nmessage User { string name = 1; }\n"
+	id, err := client.RegisterNewSchemaFromReader(context.Background(), "test", bytes.NewReader([]byte(schema)), SchemaTypeProtobuf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+}
+
+func Test_RegisterNewSchemaFromReader_with_an_avro_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "\"string\""}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.RegisterNewSchemaFromReader(context.Background(), "test", bytes.NewReader([]byte(`"string"`)), "")
+
+	assert.NoError(t, err)
+}
+
+func Test_RegisterNewSchemaFromReader_with_a_schema_too_large(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the request should not be sent")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingMaxSchemaBytes(16))
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchemaFromReader(context.Background(), "test", bytes.NewReader(bytes.Repeat([]byte("a"), 17)), "")
+
+	assert.Equal(t, -1, id)
+	assert.Equal(t, ErrSchemaTooLarge, err)
+}
+
+func Test_RegisterNewSchemaFromReader_with_the_maximum_limit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "\"string\""}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingMaxSchemaBytes(math.MaxInt64))
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchemaFromReader(context.Background(), "test", strings.NewReader(`"string"`), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func Test_RegisterNewSchemaFromReader_with_a_schema_at_the_limit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "aaaaaaaaaaaaaaaa", "schemaType": "PROTOBUF"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingMaxSchemaBytes(16))
+	require.NoError(t, err)
+
+	schema := strings.Repeat("a", 16)
+	_, err = client.RegisterNewSchemaFromReader(context.Background(), "test", strings.NewReader(schema), SchemaTypeProtobuf)
+
+	assert.NoError(t, err)
+}
+
+func Test_RegisterNewSchema_omits_the_id(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "\"string\""}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `"string"`)
+
+	assert.NoError(t, err)
+}
+
+func Test_RegisterNewSchema_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, err := client.RegisterNewSchema(context.Background(), "test", `{
+		"type": "record",
+		"name": "test",
+		"fields": [{ "type": "string", "name": "field1" }]
+    }`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+}
+
+func Test_RegisterNewSchema_with_a_created_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func Test_RegisterNewSchema_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{
+"error_code": 404,
+			"message": "subject not found"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, err := client.RegisterNewSchema(context.Background(), "test", `{
+		"type": "record",
+		"name": "test",
+		"fields": [{ "type": "string", "name": "field1" }]
+    }`)
+
+	assert.Equal(t, -1, version)
+	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/subjects/test/versions) failed with error code 404: subject not found", ts.URL))
+}
+
+func Test_RegisterNewSchema_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte("not a valid json"))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	version, err := client.RegisterNewSchema(context.Background(), "test", `{
+		"type": "record",
+		"name": "test",
+		"fields": [{ "type": "string", "name": "field1" }]
+    }`)
+
+	assert.Equal(t, -1, version)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_RegisterNewSchema_content_type(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.schemaregistry.v1+json", r.Header.Get("Content-Type"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+}
+
+func Test_RegisterNewSchema_with_a_custom_content_type(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingContentType("application/json"))
+	require.NoError(t, err)
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+}
+
+func Test_RegisterNewSchema_with_a_valid_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingSchemaValidator(func(schema string) error {
+		var doc interface{}
+		return json.Unmarshal([]byte(schema), &doc)
+	}))
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+func Test_RegisterNewSchema_with_an_invalid_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the request should not be sent")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingSchemaValidator(func(schema string) error {
+		var doc interface{}
+		return json.Unmarshal([]byte(schema), &doc)
+	}))
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"`)
+
+	assert.Equal(t, -1, id)
+	assert.EqualError(t, err, "invalid schema: unexpected end of JSON input")
+}
+
+func Test_ImportSchema_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "{\"type\": \"string\"}", "version": 3, "id": 42}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"id": 42}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.ImportSchema(context.Background(), "test", `{"type": "string"}`, 3, 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func Test_ImportSchema_without_the_import_mode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 42205,
+			"message": "Subject test is not in import mode"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.ImportSchema(context.Background(), "test", `{"type": "string"}`, 3, 42)
+
+	assert.Equal(t, -1, id)
+	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/subjects/test/versions) failed with error code 42205: Subject test is not in import mode", ts.URL))
+}
+
+func Test_GetSchemabySubjectAndVersion_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/test/versions/1", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"subject": "test",
+			"version": 1,
+			"schema": "{\"type\": \"string\"}"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Schema{
+		Subject: "test",
+		Version: 1,
+		Schema:  `{"type": "string"}`,
+	}, schema)
+}
+
+func Test_GetSchemabySubjectAndVersion_with_the_resolved_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/test/versions/1?format=resolved", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"subject": "test",
+			"version": 1,
+			"schema": "{\"type\": \"record\", \"name\": \"test\", \"fields\": [{\"name\": \"user\", \"type\": {\"type\": \"record\", \"name\": \"User\", \"fields\": []}}]}"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1, WithResolvedFormat())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Schema{
+		Subject: "test",
+		Version: 1,
+		Schema:  `{"type": "record", "name": "test", "fields": [{"name": "user", "type": {"type": "record", "name": "User", "fields": []}}]}`,
+	}, schema)
+}
+
+func Test_GetLatestSchema_with_the_resolved_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions/latest?format=resolved", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.GetLatestSchema(context.Background(), "test", WithResolvedFormat())
+
+	assert.NoError(t, err)
+}
+
+func Test_SchemaID_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/test/versions/3", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"subject": "test",
+			"id": 42,
+			"version": 3,
+			"schema": "{\"type\": \"string\"}"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.SchemaID(context.Background(), "test", 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func Test_SchemaID_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40402, "message": "version not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.SchemaID(context.Background(), "test", 3)
+
+	assert.Equal(t, -1, id)
+	assert.True(t, IsVersionNotFound(err))
+}
+
+func Test_GetLatestSchema_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/test/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"subject": "test",
+			"version": 1,
+			"schema": "{\"type\": \"string\"}"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetLatestSchema(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Schema{
+		Subject: "test",
+		Version: 1,
+		Schema:  `{"type": "string"}`,
+	}, schema)
+}
+
+func Test_GetSchemabySubjectAndVersion_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 500,
+			"message": "internal server error"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1)
+
+	assert.Nil(t, schema)
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/subjects/test/versions/1) failed with error code 500: internal server error", ts.URL))
+}
+
+func Test_GetSchemabySubjectAndVersion_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaBySubjectAndVersion(context.Background(), "test", 1)
+
+	assert.Nil(t, schema)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_IsLatest_with_the_latest_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 2, "id": 12, "schema": "{\"type\":\"record\",\"name\":\"user\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"},{\"name\":\"full name\",\"type\":\"string\"}]}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isLatest, err := client.IsLatest(context.Background(), "test", `{
+		"type": "record",
+		"name": "user",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "full name", "type": "string"}
+		]
+	}`)
+
+	assert.NoError(t, err)
+	assert.True(t, isLatest)
+}
+
+func Test_IsLatest_with_an_older_version(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 2, "id": 12, "schema": "{\"type\":\"record\",\"name\":\"user\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"},{\"name\":\"full name\",\"type\":\"string\"}]}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isLatest, err := client.IsLatest(context.Background(), "test", `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}]}`)
+
+	assert.NoError(t, err)
+	assert.False(t, isLatest)
+}
+
+func Test_IsLatest_with_a_whitespace_in_a_string(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 2, "id": 12, "schema": "{\"type\":\"record\",\"name\":\"user\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"},{\"name\":\"full name\",\"type\":\"string\"}]}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isLatest, err := client.IsLatest(context.Background(), "test", `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}, {"name": "fullname", "type": "string"}]}`)
+
+	assert.NoError(t, err)
+	assert.False(t, isLatest)
+}
+
+func Test_IsLatest_with_an_unregistered_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "Subject not found."}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isLatest, err := client.IsLatest(context.Background(), "unknown", `"string"`)
+
+	assert.NoError(t, err)
+	assert.False(t, isLatest)
+}
+
+func Test_GetLatestSchemaMetadata_with_an_id(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/test/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"subject": "test",
+			"id": 42,
+			"version": 2,
+			"schemaType": "PROTOBUF",
+			"schema": "syntax = \"proto3\";"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetLatestSchemaMetadata(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Schema{
+		Subject:    "test",
+		ID:         42,
+		Version:    2,
+		SchemaType: SchemaTypeProtobuf,
+		Schema:     `syntax = "proto3";`,
+	}, schema)
+}
+
+func Test_GetLatestSchemaMetadata_without_an_id(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "/subjects/test/versions/latest":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"subject": "test", "version": 2, "schema": "{\"type\": \"string\"}"}`))
+			require.NoError(t, err)
+		case "/subjects/test":
+			assert.Equal(t, "POST", r.Method)
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 2, "schema": "{\"type\": \"string\"}"}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetLatestSchemaMetadata(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Schema{
+		Subject:    "test",
+		ID:         42,
+		Version:    2,
+		SchemaType: SchemaTypeAvro,
+		Schema:     `{"type": "string"}`,
+	}, schema)
+}
+
+func Test_ReferencedBy_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/subjects/test/versions/2/referencedby", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[12, 15, 42]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ids, err := client.ReferencedBy(context.Background(), "test", 2)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{12, 15, 42}, ids)
+}
+
+func Test_ReferencedByLatest_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions/latest/referencedby", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ids, err := client.ReferencedByLatest(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func Test_ReferencedBy_with_an_unknown_version(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40402, "message": "version not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ids, err := client.ReferencedBy(context.Background(), "test", 2)
+
+	assert.Nil(t, ids)
+	assert.True(t, IsVersionNotFound(err))
+}
+
+func Test_GetConfig_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibility": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{
+		Compatibility: "FULL",
+	}, config)
+}
+
+func Test_GetConfig_with_the_compatibility_level_field(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{
+		Compatibility: Full,
+	}, config)
+}
+
+func Test_GetConfig_with_default_to_global(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch {
+		case r.URL.Path == "/config/override":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		case r.URL.Query().Get("defaultToGlobal") == "true":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"compatibilityLevel": "BACKWARD"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, err = w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "override", WithDefaultToGlobal())
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{Compatibility: Full}, config)
+
+	config, err = client.GetConfig(context.Background(), "no-override", WithDefaultToGlobal())
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{Compatibility: Backward}, config)
+}
+
+func Test_GetConfig_without_default_to_global(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch {
+		case r.URL.Path == "/config/override":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		case r.URL.Query().Get("defaultToGlobal") == "true":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"compatibilityLevel": "BACKWARD"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, err = w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "override")
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{Compatibility: Full}, config)
+
+	config, err = client.GetConfig(context.Background(), "no-override")
+	assert.Nil(t, config)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_GetConfig_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 500,
+			"message": "internal server error"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+
+	assert.Nil(t, config)
+	assert.EqualError(t, err, fmt.Sprintf("client: (GET: %s/config/test) failed with error code 500: internal server error", ts.URL))
+}
+
+func Test_GetConfig_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+
+	assert.Nil(t, config)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_EffectiveCompatibility_with_a_subject_level(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+
+		assert.Equal(t, "/config/override", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	level, err := client.EffectiveCompatibility(context.Background(), "override")
+
+	assert.NoError(t, err)
+	assert.Equal(t, Full, level)
+}
+
+func Test_EffectiveCompatibility_with_the_global_level(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/config/inherited":
+			status, body = http.StatusNotFound, `{"error_code": 40408, "message": "Subject 'inherited' does not have subject-level compatibility configured"}`
+		case "/config/unknown":
+			status, body = http.StatusNotFound, `{"error_code": 40401, "message": "Subject 'unknown' not found."}`
+		case "/config":
+			status, body = http.StatusOK, `{"compatibilityLevel": "BACKWARD"}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	for _, subject := range []string{"inherited", "unknown"} {
+		level, err := client.EffectiveCompatibility(context.Background(), subject)
+
+		assert.NoError(t, err, subject)
+		assert.Equal(t, Backward, level, subject)
+	}
+}
+
+func Test_EffectiveCompatibility_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+
+		assert.Equal(t, "/config/broken", r.URL.String())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	level, err := client.EffectiveCompatibility(context.Background(), "broken")
+
+	assert.Empty(t, level)
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+}
+
+func Test_DeleteSchemaVersion_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/subjects/test/versions/2?permanent=true", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`4`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+}
+
+func Test_DeleteSchemaVersion_with_an_empty_body(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, id)
+}
+
+func Test_DeleteSchemaVersion_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 500,
+			"message": "internal server error"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, false)
+
+	assert.Equal(t, -1, id)
+	assert.EqualError(t, err, fmt.Sprintf("client: (DELETE: %s/subjects/test/versions/2?permanent=false) failed with error code 500: internal server error", ts.URL))
+}
+
+func Test_DeleteSchemaVersion_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.DeleteSchemaVersion(context.Background(), "test", 2, false)
+
+	assert.Equal(t, -1, id)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_DeleteLatestSchemaVersion_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/subjects/test/versions/latest?permanent=true", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`4`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.DeleteLatestSchemaVersion(context.Background(), "test", true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, id)
+}
+
+func Test_SchemaCompatibleWith_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions/4", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"is_compatible": true
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", 4)
+
+	assert.NoError(t, err)
+	assert.True(t, isCompatible)
+}
+
+func Test_SchemaCompatibleWithLatest_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/versions/latest"), r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{
+			"is_compatible": false
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.SchemaCompatibleWithLatest(context.Background(), `{"type": "string"}`, "test")
+
+	assert.NoError(t, err)
+	assert.False(t, isCompatible)
+}
+
+func Test_SchemaCompatibleWith_latest_version(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/compatibility/subjects/test/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": true}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", LatestVersion)
+
+	assert.NoError(t, err)
+	assert.True(t, isCompatible)
+}
+
+func Test_SchemaCompatibleWithFull_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions/latest?verbose=true", r.URL.String())
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"schema":     `syntax = "proto3"; import "common.proto";`,
+			"schemaType": "PROTOBUF",
+			"references": []interface{}{
+				map[string]interface{}{"name": "common.proto", "subject": "common", "version": float64(2)},
+			},
+		}, body)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": false, "messages": ["field removed"]}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	result, err := client.SchemaCompatibleWithFull(context.Background(), CompatibilityRequest{
+		Schema:     `syntax = "proto3"; import "common.proto";`,
+		SchemaType: SchemaTypeProtobuf,
+		References: []SchemaReference{{Name: "common.proto", Subject: "common", Version: 2}},
+	}, "test", LatestVersion)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &SchemaCompatibilityResult{IsCompatible: false, Messages: []string{"field removed"}}, result)
+}
+
+func Test_SchemaCompatibleWithFull_omits_the_absent_fields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/compatibility/subjects/test/versions/3?verbose=true", r.URL.String())
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"schema": `"string"`}, body)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": true}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	result, err := client.SchemaCompatibleWithFull(context.Background(), CompatibilityRequest{
+		Schema:     `"string"`,
+		SchemaType: SchemaTypeAvro,
+	}, "test", 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &SchemaCompatibilityResult{IsCompatible: true}, result)
+}
+
+func Test_SchemaCompatibleWith_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 500,
+			"message": "internal server error"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", 2)
+
+	assert.False(t, isCompatible)
+	assert.EqualError(t, err, fmt.Sprintf("client: (POST: %s/compatibility/subjects/test/versions/2) failed with error code 500: internal server error", ts.URL))
+}
+
+func Test_SchemaCompatibleWith_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.SchemaCompatibleWith(context.Background(), `{"type": "string"}`, "test", 2)
+
+	assert.False(t, isCompatible)
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_SetGlobalConfig_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/config", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibility": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.SetGlobalConfig(context.Background(), Config{
+		Compatibility: "FULL",
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{
+		Compatibility: "FULL",
+	}, config)
+}
+
+func Test_SetGlobalConfig_with_an_empty_body(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.SetGlobalConfig(context.Background(), Config{
+		Compatibility: "FULL",
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{
+		Compatibility: "FULL",
+	}, config)
+}
+
+func Test_SetGlobalConfig_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 500,
+			"message": "internal server error"
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.SetGlobalConfig(context.Background(), Config{
+		Compatibility: "FULL",
+	})
+
+	assert.Nil(t, config)
 	assert.EqualError(t, err, fmt.Sprintf("client: (PUT: %s/config) failed with error code 500: internal server error", ts.URL))
 }
+
+func Test_TypedSchemaCompatibleWith_with_a_json_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions/4", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "{\"type\": \"string\"}", "schemaType": "JSON"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"is_compatible": true}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.TypedSchemaCompatibleWith(context.Background(), `{"type": "string"}`, SchemaTypeJSON, "test", 4)
+
+	assert.NoError(t, err)
+	assert.True(t, isCompatible)
+}
+
+func Test_TypedSchemaCompatibleWith_with_an_avro_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"schema": "{\"type\": \"string\"}"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"is_compatible": false}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.TypedSchemaCompatibleWith(context.Background(), `{"type": "string"}`, SchemaTypeAvro, "test", 4)
+
+	assert.NoError(t, err)
+	assert.False(t, isCompatible)
+}
+
+func Test_SchemaCompatibleWithSubject_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": true}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	isCompatible, err := client.SchemaCompatibleWithSubject(context.Background(), `{"type": "string"}`, "test")
+
+	assert.NoError(t, err)
+	assert.True(t, isCompatible)
+}
+
+func Test_CanRegister_with_a_new_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	alreadyRegistered, compatible, err := client.CanRegister(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.False(t, alreadyRegistered)
+	assert.True(t, compatible)
+}
+
+func Test_CanRegister_with_an_already_registered_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 1, "version": 1, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	alreadyRegistered, compatible, err := client.CanRegister(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.True(t, alreadyRegistered)
+	assert.True(t, compatible)
+}
+
+func Test_CanRegister_with_a_compatible_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "/subjects/test":
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+			require.NoError(t, err)
+		case "/compatibility/subjects/test/versions":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"is_compatible": true}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	alreadyRegistered, compatible, err := client.CanRegister(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.False(t, alreadyRegistered)
+	assert.True(t, compatible)
+}
+
+func Test_CanRegister_with_an_incompatible_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "/subjects/test":
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+			require.NoError(t, err)
+		case "/compatibility/subjects/test/versions":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"is_compatible": false}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	alreadyRegistered, compatible, err := client.CanRegister(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.False(t, alreadyRegistered)
+	assert.False(t, compatible)
+}
+
+func Test_Client_concurrent_use(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		var err error
+		if r.Method == "GET" {
+			_, err = w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
+		} else {
+			_, err = w.Write([]byte(`{"id": 42}`))
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 1}), UsingDefaults(Defaults{ReadTimeout: time.Minute}))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			schema, err := client.GetSchemaByID(context.Background(), 42)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"type": "string"}`, schema)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, id)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_Client_with_an_oversized_response(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["subject1", "subject2", "subject3"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingMaxResponseBytes(16))
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.Nil(t, subjects)
+	assert.EqualError(t, err, "the response body exceeds the limit of 16 bytes")
+}
+
+func Test_Client_with_a_response_at_the_limit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["subject1"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingMaxResponseBytes(12))
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"subject1"}, subjects)
+}
+
+func Test_Client_with_the_maximum_response_limit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["subject1"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingMaxResponseBytes(math.MaxInt64))
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"subject1"}, subjects)
+}
+
+func Test_Do_with_a_custom_endpoint(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/mode/test", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"mode": "READONLY"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"mode": "READONLY"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var res struct {
+		Mode string `json:"mode"`
+	}
+	err = client.Do(context.Background(), "PUT", "mode/test", strings.NewReader(`{"mode": "READONLY"}`), &res)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "READONLY", res.Mode)
+}
+
+func Test_Do_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.Do(context.Background(), "GET", "mode/test", nil, nil)
+
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_Do_with_an_invalid_response_format(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`not a valid json`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var res map[string]interface{}
+	err = client.Do(context.Background(), "GET", "mode/test", nil, &res)
+
+	assert.EqualError(t, err, "failed to decode the response: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func Test_SetConfig_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"compatibility": "BACKWARD_TRANSITIVE"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(`{"compatibility": "BACKWARD_TRANSITIVE"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.SetConfig(context.Background(), "test", Config{
+		Compatibility: BackwardTransitive,
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{
+		Compatibility: BackwardTransitive,
+	}, config)
+}
+
+func Test_SetConfig_with_a_compatibility_group(t *testing.T) {
+	var lock sync.Mutex
+	stored := []byte(`{"compatibilityLevel": "BACKWARD"}`)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		if r.Method == "PUT" {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			stored = body
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(stored)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config := Config{
+		Compatibility:      Backward,
+		CompatibilityGroup: "application.major.version",
+		DefaultMetadata: &Metadata{
+			Properties: map[string]string{"owner": "team-a"},
+			Tags:       map[string][]string{"user.email": {"PII"}},
+		},
+		DefaultRuleSet: &RuleSet{
+			DomainRules: []Rule{{Name: "checkEmail", Kind: "CONDITION", Mode: "WRITE", Type: "CEL", Expr: "message.email != ''"}},
+		},
+	}
+
+	updated, err := client.SetConfig(context.Background(), "test", config)
+	require.NoError(t, err)
+	assert.Equal(t, &config, updated)
+
+	read, err := client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, &config, read)
+
+	group, err := client.CompatibilityGroup(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "application.major.version", group)
+}
+
+func Test_SetConfig_with_only_a_compatibility_group(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"compatibilityGroup": "application.major.version"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.SetConfig(context.Background(), "test", Config{
+		CompatibilityGroup: "application.major.version",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{CompatibilityGroup: "application.major.version"}, config)
+}
+
+func Test_SetAndVerifyConfig_with_an_empty_level(t *testing.T) {
+	client, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	err = client.SetAndVerifyConfig(context.Background(), "test", "")
+
+	assert.EqualError(t, err, `invalid compatibility level ""`)
+}
+
+func Test_SetConfig_without_a_compatibility_group(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"compatibility": "FULL"}`, string(body))
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.SetConfig(context.Background(), "test", Config{Compatibility: Full})
+
+	assert.NoError(t, err)
+}
+
+func Test_CompatibilityGroup_without_group(t *testing.T) {
+	var lock sync.Mutex
+	stored := []byte(`{"compatibilityLevel": "BACKWARD"}`)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		if r.Method == "PUT" {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			stored = body
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(stored)
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	group, err := client.CompatibilityGroup(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.Empty(t, group)
+}
+
+func Test_CompatibilityGroup_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	group, err := client.CompatibilityGroup(context.Background(), "test")
+
+	assert.Empty(t, group)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_SetConfig_with_an_invalid_level(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the request should not be sent")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	config, err := client.SetConfig(context.Background(), "test", Config{
+		Compatibility: "BACKWARDS",
+	})
+
+	assert.Nil(t, config)
+	assert.EqualError(t, err, `invalid compatibility level "BACKWARDS"`)
+}
+
+func Test_RegisterIfCompatible_with_a_compatible_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/compatibility/subjects/test/versions?verbose=true":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"is_compatible": true}`))
+		case "/subjects/test/versions":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"id": 12}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, id)
+}
+
+func Test_RegisterIfCompatible_with_an_incompatible_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/compatibility/subjects/test/versions?verbose=true", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": false, "messages": ["READER_FIELD_MISSING_DEFAULT_VALUE", "name"]}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, id)
+	assert.True(t, IsIncompatibleSchema(err))
+	assert.Equal(t, IncompatibleSchemaError{
+		Subject:  "test",
+		Messages: []string{"READER_FIELD_MISSING_DEFAULT_VALUE", "name"},
+	}, err)
+}
+
+func Test_RegisterIfCompatible_with_a_new_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/compatibility/subjects/test/versions?verbose=true":
+			w.WriteHeader(http.StatusNotFound)
+			_, err = w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		case "/subjects/test/versions":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"id": 1}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+}
+
+// longReadsOnlyLong is a compatibility checker where only a long schema can
+// read long data.
+func longReadsOnlyLong(readerSchema string, writerSchema string) (bool, error) {
+	return writerSchema != `{"type": "long"}` || readerSchema == `{"type": "long"}`, nil
+}
+
+func Test_RegisterIfCompatible_with_a_none_subject_and_an_incompatible_schema(t *testing.T) {
+	var registrations int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/compatibility/subjects/test/versions?verbose=true":
+			_, err = w.Write([]byte(`{"is_compatible": true}`))
+		case "/config/test":
+			_, err = w.Write([]byte(`{"compatibilityLevel": "NONE"}`))
+		case "/subjects/test/versions/latest":
+			_, err = w.Write([]byte(`{"subject": "test", "version": 3, "id": 11, "schema": "{\"type\": \"long\"}"}`))
+		case "/subjects/test/versions":
+			atomic.AddInt32(&registrations, 1)
+			_, err = w.Write([]byte(`{"id": 12}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingCompatibilityChecker(longReadsOnlyLong))
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, id)
+	assert.Equal(t, IncompatibleSchemaError{
+		Subject:  "test",
+		Messages: []string{"the schema can't read the version 3 of the subject with the NONE level"},
+	}, err)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&registrations))
+}
+
+func Test_RegisterIfCompatible_with_a_none_subject_and_a_compatible_schema(t *testing.T) {
+	var registrations int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/compatibility/subjects/test/versions?verbose=true":
+			_, err = w.Write([]byte(`{"is_compatible": true}`))
+		case "/config/test":
+			_, err = w.Write([]byte(`{"compatibilityLevel": "NONE"}`))
+		case "/subjects/test/versions/latest":
+			_, err = w.Write([]byte(`{"subject": "test", "version": 3, "id": 11, "schema": "{\"type\": \"long\"}"}`))
+		case "/subjects/test/versions":
+			atomic.AddInt32(&registrations, 1)
+			_, err = w.Write([]byte(`{"id": 12}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingCompatibilityChecker(longReadsOnlyLong))
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "long"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, id)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&registrations))
+}
+
+func Test_RegisterIfCompatible_with_a_none_subject_without_checker(t *testing.T) {
+	var registrations int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/compatibility/subjects/test/versions?verbose=true":
+			_, err = w.Write([]byte(`{"is_compatible": true}`))
+		case "/subjects/test/versions":
+			atomic.AddInt32(&registrations, 1)
+			_, err = w.Write([]byte(`{"id": 12}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, id)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&registrations))
+}
+
+func Test_RegisterIfCompatible_with_a_checker_and_a_backward_subject(t *testing.T) {
+	var registrations int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/compatibility/subjects/test/versions?verbose=true":
+			_, err = w.Write([]byte(`{"is_compatible": true}`))
+		case "/config/test":
+			_, err = w.Write([]byte(`{"compatibilityLevel": "BACKWARD"}`))
+		case "/subjects/test/versions":
+			atomic.AddInt32(&registrations, 1)
+			_, err = w.Write([]byte(`{"id": 12}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingCompatibilityChecker(func(readerSchema string, writerSchema string) (bool, error) {
+		assert.Fail(t, "the server check is enough")
+		return false, nil
+	}))
+	require.NoError(t, err)
+
+	id, err := client.RegisterIfCompatible(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, id)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&registrations))
+}
+
+func Test_RegisterNewSchema_with_a_detailed_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{
+			"error_code": 42201,
+			"message": "Invalid schema",
+			"details": "Unknown type: strin",
+			"field": {"name": "value", "line": 3}
+		}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `{"type": "strin"}`)
+
+	var resErr ResourceError
+	require.True(t, errors.As(err, &resErr))
+	assert.True(t, IsInvalidSchema(err))
+	assert.Equal(t, "Invalid schema", resErr.Message)
+	assert.Equal(t, map[string]interface{}{
+		"details": "Unknown type: strin",
+		"field":   map[string]interface{}{"name": "value", "line": float64(3)},
+	}, resErr.Details)
+}
+
+func Test_GetLatestSchema_with_an_unknown_field(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "schema": "\"string\"", "schemaTags": []}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	schema, err := client.GetLatestSchema(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, schema.ID)
+}
+
+func Test_GetLatestSchema_with_an_unknown_field_and_strict_decoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "schema": "\"string\"", "schemaTags": []}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingStrictDecoding())
+	require.NoError(t, err)
+
+	schema, err := client.GetLatestSchema(context.Background(), "test")
+
+	assert.Nil(t, schema)
+	assert.EqualError(t, err, `failed to decode the response: json: unknown field "schemaTags"`)
+}
+
+func Test_GetConfig_with_an_unknown_field_and_strict_decoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL", "unknownField": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingStrictDecoding())
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+
+	assert.Nil(t, config)
+	assert.EqualError(t, err, `failed to decode the response: json: unknown field "unknownField"`)
+}
+
+func Test_GetConfig_with_strict_decoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL", "compatibilityGroup": "application.version"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingStrictDecoding())
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+
+	require.NoError(t, err)
+	assert.Equal(t, &Config{Compatibility: Full, CompatibilityGroup: "application.version"}, config)
+}
+
+func Test_GetSchemaByID_with_strict_decoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"schema": "\"string\"", "schemaType": "AVRO", "maxId": 3}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingStrictDecoding())
+	require.NoError(t, err)
+
+	schema, err := client.GetSchemaByID(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"string"`, schema)
+}
+
+func Test_Subjects_following_a_redirect(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/subjects" {
+			http.Redirect(w, r, "/moved/subjects", http.StatusFound)
+			return
+		}
+
+		assert.Equal(t, "/moved/subjects", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test"}, subjects)
+}
+
+func Test_Subjects_with_a_redirect_policy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects", r.URL.Path)
+		http.Redirect(w, r, "/moved/subjects", http.StatusFound)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRedirectPolicy(func(req *http.Request, via []*http.Request) error {
+		return errors.New("redirects are forbidden")
+	}))
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.Nil(t, subjects)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redirects are forbidden")
+}
+
+func Test_UsingRedirectPolicy_with_a_provided_client(t *testing.T) {
+	httpClient := &http.Client{}
+
+	_, err := NewClient("http://localhost", UsingClient(httpClient), UsingRedirectPolicy(func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}))
+	require.NoError(t, err)
+
+	assert.Nil(t, httpClient.CheckRedirect)
+}
+
+func Test_RegisterNewSchema_with_verbose_incompatibility(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/subjects/test/versions":
+			w.WriteHeader(http.StatusConflict)
+			_, err = w.Write([]byte(`{"error_code": 409, "message": "Schema being registered is incompatible with an earlier schema"}`))
+		case "/compatibility/subjects/test/versions?verbose=true":
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"is_compatible": false, "messages": ["READER_FIELD_MISSING_DEFAULT_VALUE"]}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingVerboseIncompatibility())
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, id)
+	assert.EqualError(t, err, "schema incompatible with the subject test: READER_FIELD_MISSING_DEFAULT_VALUE")
+	assert.True(t, IsIncompatibleSchema(err))
+	assert.True(t, HasErrorCode(err, IncompatibleSchema))
+}
+
+func Test_RegisterNewSchema_with_an_incompatible_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		w.WriteHeader(http.StatusConflict)
+		_, err := w.Write([]byte(`{"error_code": 409, "message": "Schema being registered is incompatible with an earlier schema"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, id)
+	assert.IsType(t, ResourceError{}, err)
+	assert.True(t, IsIncompatibleSchema(err))
+}
+
+func Test_UsingConnectionPool(t *testing.T) {
+	client, err := NewClient("http://localhost", UsingConnectionPool(200, 50, time.Minute))
+	require.NoError(t, err)
+
+	transport := client.client.Transport.(*http.Transport)
+	assert.Equal(t, 200, transport.MaxIdleConns)
+	assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	assert.NotEqual(t, 50, defaultTransport.MaxIdleConnsPerHost)
+}
+
+func Test_UsingConnectionPool_with_a_provided_client(t *testing.T) {
+	transport := &http.Transport{}
+
+	_, err := NewClient("http://localhost", UsingClient(&http.Client{Transport: transport}), UsingConnectionPool(200, 50, time.Minute))
+	require.NoError(t, err)
+
+	assert.Zero(t, transport.MaxIdleConnsPerHost)
+}
+
+func Test_UsingHTTP1Only(t *testing.T) {
+	client, err := NewClient("http://localhost", UsingHTTP1Only())
+	require.NoError(t, err)
+
+	transport := client.client.Transport.(*http.Transport)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto)
+	assert.Empty(t, transport.TLSNextProto)
+
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	assert.True(t, defaultTransport.ForceAttemptHTTP2)
+}
+
+func Test_UsingHTTP1Only_with_a_provided_client(t *testing.T) {
+	transport := &http.Transport{ForceAttemptHTTP2: true}
+
+	_, err := NewClient("http://localhost", UsingClient(&http.Client{Transport: transport}), UsingHTTP1Only())
+	require.NoError(t, err)
+
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.Nil(t, transport.TLSNextProto)
+}
+
+func Test_UsingDefaultTimeout_with_a_background_context(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(time.Second):
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingDefaultTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.Nil(t, subjects)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func Test_UsingDefaultTimeout_with_a_context_deadline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingDefaultTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	subjects, err := client.Subjects(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test"}, subjects)
+}
+
+func Test_DeleteSubject_with_force(t *testing.T) {
+	var queries []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		queries = append(queries, r.URL.RawQuery)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.DeleteSubject(context.Background(), "test", true)
+	require.NoError(t, err)
+	_, err = client.DeleteSubject(context.Background(), "test", true, WithForce())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"permanent=true", "permanent=true&force=true"}, queries)
+}
+
+func Test_DeleteSchemaVersion_with_force(t *testing.T) {
+	var urls []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		urls = append(urls, r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`1`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.DeleteSchemaVersion(context.Background(), "test", 1, false)
+	require.NoError(t, err)
+	_, err = client.DeleteSchemaVersion(context.Background(), "test", 1, true, WithForce())
+	require.NoError(t, err)
+	_, err = client.DeleteLatestSchemaVersion(context.Background(), "test", true, WithForce())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/subjects/test/versions/1?permanent=false",
+		"/subjects/test/versions/1?permanent=true&force=true",
+		"/subjects/test/versions/latest?permanent=true&force=true",
+	}, urls)
+}
+
+// apicurioError is the shape of the errors returned by Apicurio.
+type apicurioError struct {
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+}
+
+func (err apicurioError) Error() string {
+	return fmt.Sprintf("%d: %s", err.Status, err.Title)
+}
+
+func parseApicurioError(res *http.Response) error {
+	var apiErr apicurioError
+	if json.NewDecoder(res.Body).Decode(&apiErr) != nil || apiErr.Title == "" {
+		return nil
+	}
+
+	return apiErr
+}
+
+func Test_UsingErrorParser_with_a_custom_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"title": "No artifact with ID 'test'", "status": 404}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingErrorParser(parseApicurioError))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.Nil(t, versions)
+	assert.Equal(t, apicurioError{Title: "No artifact with ID 'test'", Status: 404}, err)
+}
+
+func Test_UsingErrorParser_falling_back_to_the_default_parser(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingErrorParser(parseApicurioError))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.Nil(t, versions)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_UsingErrorParser_with_a_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingErrorParser(func(res *http.Response) error {
+		assert.Fail(t, "unexpected call of the error parser")
+		return nil
+	}))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, versions)
+}
+
+func Test_UsingAPIPrefix(t *testing.T) {
+	var paths []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1, "schema": "\"string\"", "compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		baseURL string
+		prefix  string
+	}{
+		{baseURL: ts.URL, prefix: "apis/ccompat/v7"},
+		{baseURL: ts.URL + "/", prefix: "/apis/ccompat/v7/"},
+		{baseURL: ts.URL + "/registry", prefix: "apis/ccompat/v7"},
+	} {
+		paths = nil
+
+		client, err := NewClient(tc.baseURL, UsingAPIPrefix(tc.prefix))
+		require.NoError(t, err)
+
+		_, err = client.GetSchemaByID(context.Background(), 1)
+		require.NoError(t, err)
+		_, err = client.GetConfig(context.Background(), "test", WithDefaultToGlobal())
+		require.NoError(t, err)
+		_, err = client.RegisterNewSchema(context.Background(), "test", `"string"`)
+		require.NoError(t, err)
+
+		base := strings.TrimPrefix(strings.TrimSuffix(tc.baseURL, "/"), ts.URL)
+		assert.Equal(t, []string{
+			base + "/apis/ccompat/v7/schemas/ids/1",
+			base + "/apis/ccompat/v7/config/test?defaultToGlobal=true",
+			base + "/apis/ccompat/v7/subjects/test/versions",
+		}, paths, tc)
+	}
+}
+
+func Test_UsingHost(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "registry.internal", r.Host)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingHost("registry.internal"))
+	require.NoError(t, err)
+
+	_, err = client.GetSchemaByID(context.Background(), 1)
+	assert.NoError(t, err)
+}
+
+func Test_UsingAcceptLanguage(t *testing.T) {
+	var languages []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		languages = append(languages, r.Header.Get("Accept-Language"))
+
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusConflict)
+			_, err := w.Write([]byte(`{"error_code": 409, "message": "Schéma incompatible"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingAcceptLanguage("fr-FR, fr;q=0.9"))
+	require.NoError(t, err)
+
+	_, err = client.GetSchemaByID(context.Background(), 1)
+	assert.NoError(t, err)
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `"string"`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed with error code 409: Schéma incompatible")
+
+	assert.Equal(t, []string{"fr-FR, fr;q=0.9", "fr-FR, fr;q=0.9"}, languages)
+}
+
+func Test_UsingHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"a", "b"}, r.Header["X-Tenant"])
+		assert.Equal(t, "en", r.Header.Get("Accept-Language"))
+		assert.Equal(t, schemaRegistryMediaType, r.Header.Get("Content-Type"))
+		assert.Equal(t, defaultAccept, r.Header.Get("Accept"))
+
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	headers := http.Header{}
+	headers.Add("X-Tenant", "a")
+	headers.Set("Accept", "text/plain")
+	headers.Set("Content-Type", "text/plain")
+
+	client, err := NewClient(ts.URL,
+		UsingHeaders(headers),
+		UsingHeaders(http.Header{"X-Tenant": {"b"}}),
+		UsingAcceptLanguage("en"),
+	)
+	require.NoError(t, err)
+
+	headers.Set("X-Tenant", "modified")
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `"string"`)
+	assert.NoError(t, err)
+}
+
+func Test_SetAndVerifyConfig_with_an_immediate_visibility(t *testing.T) {
+	var reads int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		body := `{"compatibility": "FULL"}`
+		if r.Method == "GET" {
+			atomic.AddInt32(&reads, 1)
+			body = `{"compatibilityLevel": "FULL"}`
+		}
+
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, usingClock(clk))
+	require.NoError(t, err)
+
+	err = client.SetAndVerifyConfig(context.Background(), "test", Full)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&reads))
+	assert.Empty(t, clk.Waits())
+}
+
+func Test_SetAndVerifyConfig_with_a_delayed_visibility(t *testing.T) {
+	var reads int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		var err error
+		switch {
+		case r.Method == "PUT":
+			_, err = w.Write([]byte(`{"compatibility": "FULL"}`))
+		case atomic.AddInt32(&reads, 1) <= 2:
+			_, err = w.Write([]byte(`{"compatibilityLevel": "BACKWARD"}`))
+		default:
+			_, err = w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		}
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, usingClock(clk), UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	err = client.SetAndVerifyConfig(context.Background(), "test", Full)
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&reads))
+	assert.Len(t, clk.Waits(), 2)
+}
+
+func Test_SetAndVerifyConfig_without_visibility(t *testing.T) {
+	var reads int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config/test", r.URL.String())
+
+		body := `{"compatibility": "FULL"}`
+		if r.Method == "GET" {
+			atomic.AddInt32(&reads, 1)
+			body = `{"compatibilityLevel": "BACKWARD"}`
+		}
+
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, usingClock(clk))
+	require.NoError(t, err)
+
+	err = client.SetAndVerifyConfig(context.Background(), "test", Full)
+
+	assert.EqualError(t, err, "the compatibility level of test is still BACKWARD instead of FULL after 5 reads")
+	assert.EqualValues(t, 5, atomic.LoadInt32(&reads))
+}
+
+func Test_SetAndVerifyConfig_with_an_invalid_level(t *testing.T) {
+	client, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	err = client.SetAndVerifyConfig(context.Background(), "test", "BACKWARDS")
+
+	assert.EqualError(t, err, `invalid compatibility level "BACKWARDS"`)
+}
@@ -0,0 +1,28 @@
+package schemaregistry
+
+import "time"
+
+// clock provides the time to the time dependent code (retries, caches, polls)
+// so the tests can control it.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the clock used by default, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// usingClock replaces the clock of the client, it is meant for the tests.
+func usingClock(clk clock) Option {
+	return func(c *Client) {
+		c.clock = clk
+	}
+}
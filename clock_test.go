@@ -0,0 +1,54 @@
+package schemaregistry
+
+import (
+	"sync"
+	"time"
+)
+
+// fakeClock is a clock advancing only when told to. The waits don't block:
+// they advance the clock by the waited duration and return immediately, the
+// durations being recorded.
+type fakeClock struct {
+	lock  sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.now = f.now.Add(d)
+	f.waits = append(f.waits, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- f.now
+	return ch
+}
+
+// Advance moves the clock forward.
+func (f *fakeClock) Advance(d time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.now = f.now.Add(d)
+}
+
+// Waits returns the durations waited so far.
+func (f *fakeClock) Waits() []time.Duration {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return append([]time.Duration(nil), f.waits...)
+}
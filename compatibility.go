@@ -0,0 +1,67 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoCompatibilityChecker is returned by AreCompatible when no checker have
+// been set with `UsingCompatibilityChecker`.
+var ErrNoCompatibilityChecker = errors.New("no compatibility checker configured")
+
+// CompatibilityLevel describes how the new schemas of a subject are checked
+// against its previous versions.
+//
+// https://docs.confluent.io/current/schema-registry/avro.html#compatibility-types
+type CompatibilityLevel string
+
+// These are the compatibility levels supported by the registry.
+const (
+	Backward           CompatibilityLevel = "BACKWARD"
+	BackwardTransitive CompatibilityLevel = "BACKWARD_TRANSITIVE"
+	Forward            CompatibilityLevel = "FORWARD"
+	ForwardTransitive  CompatibilityLevel = "FORWARD_TRANSITIVE"
+	Full               CompatibilityLevel = "FULL"
+	FullTransitive     CompatibilityLevel = "FULL_TRANSITIVE"
+	None               CompatibilityLevel = "NONE"
+)
+
+// Valid tells if the level is one of the levels supported by the registry.
+func (l CompatibilityLevel) Valid() bool {
+	switch l {
+	case Backward, BackwardTransitive, Forward, ForwardTransitive, Full, FullTransitive, None:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsingCompatibilityChecker sets a function telling if data written with the
+// writer schema can be read with the reader schema, used by `AreCompatible`.
+// It is typically backed by an Avro library.
+func UsingCompatibilityChecker(checker func(readerSchema string, writerSchema string) (bool, error)) Option {
+	return func(c *Client) {
+		c.compatibilityChecker = checker
+	}
+}
+
+// AreCompatible tells if data written with the writer schema can be read with
+// the reader schema, without any registered subject.
+//
+// The registry only checks the schemas against registered versions, so the
+// check is made on the client side with the function set by
+// `UsingCompatibilityChecker`. ErrNoCompatibilityChecker is returned if there
+// is none.
+func (c *Client) AreCompatible(ctx context.Context, readerSchema string, writerSchema string) (bool, error) {
+	ctx = withOperation(ctx, "AreCompatible")
+
+	if c.compatibilityChecker == nil {
+		return false, ErrNoCompatibilityChecker
+	}
+
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return c.compatibilityChecker(readerSchema, writerSchema)
+}
@@ -0,0 +1,76 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompatibilityLevel_Valid(t *testing.T) {
+	for _, level := range []CompatibilityLevel{Backward, BackwardTransitive, Forward, ForwardTransitive, Full, FullTransitive, None} {
+		assert.True(t, level.Valid(), level)
+	}
+}
+
+func Test_CompatibilityLevel_Valid_with_invalid_levels(t *testing.T) {
+	for _, level := range []CompatibilityLevel{"", "BACKWARDS", "backward"} {
+		assert.False(t, level.Valid(), level)
+	}
+}
+
+func Test_Config_json_round_trip(t *testing.T) {
+	raw, err := json.Marshal(Config{Compatibility: FullTransitive})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"compatibility": "FULL_TRANSITIVE"}`, string(raw))
+
+	var config Config
+	err = json.Unmarshal(raw, &config)
+	require.NoError(t, err)
+	assert.Equal(t, FullTransitive, config.Compatibility)
+}
+
+// stringChecker considers compatible the schemas reading a field written by the
+// writer schema, a schema being the list of its fields.
+func stringChecker(readerSchema string, writerSchema string) (bool, error) {
+	for _, field := range strings.Split(readerSchema, ",") {
+		if !strings.Contains(writerSchema, field) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func Test_AreCompatible_with_a_compatible_pair(t *testing.T) {
+	client, err := NewClient("http://localhost", UsingCompatibilityChecker(stringChecker))
+	require.NoError(t, err)
+
+	compatible, err := client.AreCompatible(context.Background(), "id", "id,name")
+
+	assert.NoError(t, err)
+	assert.True(t, compatible)
+}
+
+func Test_AreCompatible_with_an_incompatible_pair(t *testing.T) {
+	client, err := NewClient("http://localhost", UsingCompatibilityChecker(stringChecker))
+	require.NoError(t, err)
+
+	compatible, err := client.AreCompatible(context.Background(), "id,name", "id")
+
+	assert.NoError(t, err)
+	assert.False(t, compatible)
+}
+
+func Test_AreCompatible_without_checker(t *testing.T) {
+	client, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	compatible, err := client.AreCompatible(context.Background(), "id", "id")
+
+	assert.False(t, compatible)
+	assert.Equal(t, ErrNoCompatibilityChecker, err)
+}
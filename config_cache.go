@@ -0,0 +1,89 @@
+package schemaregistry
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// UsingConfigCache caches the configurations returned by GetConfig for the
+// given duration, per subject and options. A configuration updated on the
+// server is seen once the cached entry expired, or immediately if it have been
+// updated with this client.
+//
+// The configurations are not cached by default.
+func UsingConfigCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.configCache = &configCache{
+			ttl:     ttl,
+			entries: map[string]configCacheEntry{},
+		}
+	}
+}
+
+type configCacheEntry struct {
+	config    Config
+	expiresAt time.Time
+}
+
+// configCache is a TTL cache of the configurations keyed by request path. It
+// is safe for concurrent use.
+type configCache struct {
+	ttl time.Duration
+
+	lock    sync.RWMutex
+	entries map[string]configCacheEntry
+}
+
+// get returns a copy of the cached configuration, if it have not expired at
+// the given time.
+func (cc *configCache) get(path string, now time.Time) (*Config, bool) {
+	cc.lock.RLock()
+	defer cc.lock.RUnlock()
+
+	entry, ok := cc.entries[path]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	config := entry.config.clone()
+	return &config, true
+}
+
+func (cc *configCache) set(path string, config Config, now time.Time) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	cc.entries[path] = configCacheEntry{
+		config:    config.clone(),
+		expiresAt: now.Add(cc.ttl),
+	}
+}
+
+// clear drops all the entries. A global configuration change affects the
+// subjects without configuration, so all of them are dropped on any change.
+func (cc *configCache) clear() {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	cc.entries = map[string]configCacheEntry{}
+}
+
+// clone returns a deep copy of the configuration, so the cached ones can't be
+// modified through their metadata or rule set.
+func (c Config) clone() Config {
+	if c.DefaultMetadata == nil && c.DefaultRuleSet == nil {
+		return c
+	}
+
+	// nolint
+	// Error not possible here, the configuration is a plain JSON value.
+	raw, _ := json.Marshal(c)
+
+	var clone Config
+	// nolint
+	// Error not possible here, the JSON have just been encoded.
+	_ = json.Unmarshal(raw, &clone)
+
+	return clone
+}
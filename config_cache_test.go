@@ -0,0 +1,182 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetConfig_with_a_cache_hit(t *testing.T) {
+	var reads int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			atomic.AddInt32(&reads, 1)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		config, err := client.GetConfig(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Config{Compatibility: Full}, config)
+	}
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&reads))
+
+	_, err = client.GetConfig(context.Background(), "test", WithDefaultToGlobal())
+	assert.NoError(t, err)
+	_, err = client.GetConfig(context.Background(), "")
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, 3, atomic.LoadInt32(&reads))
+}
+
+func Test_GetConfig_with_a_cache_hit_returns_a_copy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL", "defaultMetadata": {"properties": {"owner": "team-a"}}}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	config, err := client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+	config.DefaultMetadata.Properties["owner"] = "modified"
+
+	config, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+	config.DefaultMetadata.Properties["owner"] = "modified"
+
+	config, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", config.DefaultMetadata.Properties["owner"])
+}
+
+func Test_GetConfig_with_an_expired_cache_entry(t *testing.T) {
+	var reads int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			atomic.AddInt32(&reads, 1)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Minute), usingClock(clk))
+	require.NoError(t, err)
+
+	_, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+
+	clk.Advance(59 * time.Second)
+
+	_, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&reads))
+
+	clk.Advance(time.Second)
+
+	_, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&reads))
+}
+
+func Test_GetConfig_with_a_cache_cleared_by_an_update(t *testing.T) {
+	var reads int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			atomic.AddInt32(&reads, 1)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	_, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+
+	_, err = client.SetGlobalConfig(context.Background(), Config{Compatibility: Full})
+	require.NoError(t, err)
+
+	_, err = client.GetConfig(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&reads))
+}
+
+func Test_GetConfig_without_cache(t *testing.T) {
+	var reads int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			atomic.AddInt32(&reads, 1)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetConfig(context.Background(), "test")
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 3, atomic.LoadInt32(&reads))
+}
+
+func Test_GetConfig_with_a_cache_and_concurrent_calls(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			config, err := client.GetConfig(context.Background(), "test")
+			assert.NoError(t, err)
+			assert.Equal(t, &Config{Compatibility: Full}, config)
+		}()
+	}
+
+	wg.Wait()
+}
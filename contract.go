@@ -0,0 +1,38 @@
+package schemaregistry
+
+// Metadata is the metadata attached to a schema version by the data contracts
+// of the Confluent registries (7.4+).
+//
+// https://docs.confluent.io/platform/current/schema-registry/fundamentals/data-contracts.html
+type Metadata struct {
+	// Tags are the tags of the schema paths, e.g. the "PII" tag of a field.
+	Tags map[string][]string `json:"tags,omitempty"`
+	// Properties are free key/value pairs, e.g. the owner of the schema.
+	Properties map[string]string `json:"properties,omitempty"`
+	// Sensitive are the names of the properties holding sensitive values.
+	Sensitive []string `json:"sensitive,omitempty"`
+}
+
+// RuleSet is the set of rules attached to a schema version by the data
+// contracts of the Confluent registries (7.4+).
+type RuleSet struct {
+	// MigrationRules transform the data between the versions.
+	MigrationRules []Rule `json:"migrationRules,omitempty"`
+	// DomainRules validate or transform the data of the version.
+	DomainRules []Rule `json:"domainRules,omitempty"`
+}
+
+// Rule is a data contract rule, executed by the serializers.
+type Rule struct {
+	Name      string            `json:"name"`
+	Doc       string            `json:"doc,omitempty"`
+	Kind      string            `json:"kind,omitempty"`
+	Mode      string            `json:"mode,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Params    map[string]string `json:"params,omitempty"`
+	Expr      string            `json:"expr,omitempty"`
+	OnSuccess string            `json:"onSuccess,omitempty"`
+	OnFailure string            `json:"onFailure,omitempty"`
+	Disabled  bool              `json:"disabled,omitempty"`
+}
@@ -0,0 +1,43 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+// CredentialsProvider provides the value of the Authorization header of the
+// requests. It is called for each request so the tokens can be rotated, and
+// must be safe for concurrent use.
+type CredentialsProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// UsingCredentialsProvider sets the provider of the Authorization header, see
+// `BasicAuthCredentials` and `BearerCredentials` for the static credentials.
+func UsingCredentialsProvider(provider CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithBasicAuth authenticates the requests with the HTTP basic authentication.
+func WithBasicAuth(user string, password string) Option {
+	return UsingCredentialsProvider(BasicAuthCredentials(user, password))
+}
+
+type staticCredentials string
+
+func (s staticCredentials) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// BasicAuthCredentials returns a provider of static HTTP basic authentication
+// credentials.
+func BasicAuthCredentials(user string, password string) CredentialsProvider {
+	return staticCredentials("Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password)))
+}
+
+// BearerCredentials returns a provider of a static bearer token.
+func BearerCredentials(token string) CredentialsProvider {
+	return staticCredentials("Bearer " + token)
+}
@@ -0,0 +1,128 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rotatingCredentials struct {
+	calls int32
+}
+
+func (r *rotatingCredentials) Token(ctx context.Context) (string, error) {
+	return fmt.Sprintf("Bearer token-%d", atomic.AddInt32(&r.calls, 1)), nil
+}
+
+func Test_UsingCredentialsProvider_with_rotating_tokens(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprintf("Bearer token-%d", atomic.AddInt32(&calls, 1)), r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingCredentialsProvider(&rotatingCredentials{}))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Subjects(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func Test_WithBasicAuth(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, `Basic dXNlcjpzZWNyZXQ=`, r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, WithBasicAuth("user", "secret"))
+	require.NoError(t, err)
+
+	_, err = client.Subjects(context.Background())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func Test_BearerCredentials(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, `Bearer some-token`, r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingCredentialsProvider(BearerCredentials("some-token")))
+	require.NoError(t, err)
+
+	_, err = client.Subjects(context.Background())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func Test_Client_without_credentials(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, ``, r.Header.Get("Authorization"))
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`["test"]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.Subjects(context.Background())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+type failingCredentials struct{}
+
+func (failingCredentials) Token(ctx context.Context) (string, error) {
+	return "", errors.New("token expired")
+}
+
+func Test_UsingCredentialsProvider_with_an_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the request should not be sent")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingCredentialsProvider(failingCredentials{}))
+	require.NoError(t, err)
+
+	subjects, err := client.Subjects(context.Background())
+
+	assert.Nil(t, subjects)
+	assert.EqualError(t, err, "failed to get the credentials: token expired")
+}
@@ -0,0 +1,54 @@
+package schemaregistry
+
+import (
+	"context"
+	"sync"
+)
+
+// Deserializer resolves the schemas of the messages framed with the Confluent
+// wire format.
+//
+// The schemas are cached by ID so a network call is made only the first time
+// an ID is seen. A Deserializer is safe for concurrent use.
+type Deserializer struct {
+	client Registry
+
+	lock  sync.RWMutex
+	cache map[int]string
+}
+
+// NewDeserializer instantiate a new Deserializer using the given registry client.
+func NewDeserializer(client Registry) *Deserializer {
+	return &Deserializer{
+		client: client,
+		cache:  map[int]string{},
+	}
+}
+
+// Schema returns the schema used to encode the message with the payload
+// stripped from its wire-format header.
+func (d *Deserializer) Schema(ctx context.Context, message []byte) (schema string, payload []byte, err error) {
+	id, payload, err := DecodePayload(message)
+	if err != nil {
+		return "", nil, err
+	}
+
+	d.lock.RLock()
+	schema, ok := d.cache[id]
+	d.lock.RUnlock()
+
+	if ok {
+		return schema, payload, nil
+	}
+
+	schema, err = d.client.GetSchemaByID(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	d.lock.Lock()
+	d.cache[id] = schema
+	d.lock.Unlock()
+
+	return schema, payload, nil
+}
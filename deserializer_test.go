@@ -0,0 +1,99 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Deserializer_Schema_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/schemas/ids/42", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	deserializer := NewDeserializer(client)
+
+	schema, payload, err := deserializer.Schema(context.Background(), EncodePayload(42, []byte("some-payload")))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type": "string"}`, schema)
+	assert.Equal(t, []byte("some-payload"), payload)
+}
+
+func Test_Deserializer_Schema_with_a_cache_hit(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{ "schema": "{\"type\": \"string\"}" }`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	deserializer := NewDeserializer(client)
+
+	_, _, err = deserializer.Schema(context.Background(), EncodePayload(42, []byte("first-payload")))
+	require.NoError(t, err)
+
+	schema, payload, err := deserializer.Schema(context.Background(), EncodePayload(42, []byte("second-payload")))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type": "string"}`, schema)
+	assert.Equal(t, []byte("second-payload"), payload)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_Deserializer_Schema_with_a_malformed_prefix(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "the request should not be sent")
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	deserializer := NewDeserializer(client)
+
+	schema, payload, err := deserializer.Schema(context.Background(), []byte{0x01, 0x00, 0x00, 0x00, 0x2a})
+
+	assert.Empty(t, schema)
+	assert.Nil(t, payload)
+	assert.EqualError(t, err, "invalid message: unknown magic byte 0x1")
+}
+
+func Test_Deserializer_Schema_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40403, "message": "schema not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	deserializer := NewDeserializer(client)
+
+	schema, payload, err := deserializer.Schema(context.Background(), EncodePayload(42, nil))
+
+	assert.Empty(t, schema)
+	assert.Nil(t, payload)
+	assert.True(t, IsSchemaNotFound(err))
+}
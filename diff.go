@@ -0,0 +1,187 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Diff is the field-level difference between two Avro schemas. The fields of
+// the nested records are named by their path (e.g. "address.street").
+type Diff struct {
+	// Added are the fields found only in the new schema
+	Added []DiffField
+	// Removed are the fields found only in the old schema
+	Removed []DiffField
+	// Changed are the fields found in both schemas with a different type
+	Changed []FieldChange
+}
+
+// DiffField is a field added or removed between two schemas.
+type DiffField struct {
+	Name string
+	Type interface{}
+}
+
+// FieldChange is a field whose type changed between two schemas. The name is
+// empty when the schemas aren't records and the whole schema changed.
+type FieldChange struct {
+	Name string
+	From interface{}
+	To   interface{}
+}
+
+// IsEmpty tells if the schemas have the same fields.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// SchemaDiff returns the field-level difference between two versions of the
+// Avro schemas of the subject, from the old version to the new one.
+func (c *Client) SchemaDiff(ctx context.Context, subject string, fromVersion, toVersion int) (Diff, error) {
+	ctx = withOperation(ctx, "SchemaDiff")
+
+	from, err := c.GetSchemaBySubjectAndVersion(ctx, subject, fromVersion)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	to, err := c.GetSchemaBySubjectAndVersion(ctx, subject, toVersion)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return DiffSchemas(from, to)
+}
+
+// DiffSchemas returns the field-level difference between two Avro schemas,
+// from the old schema to the new one.
+func DiffSchemas(from, to *Schema) (Diff, error) {
+	fromAvro, err := from.ParseAvro()
+	if err != nil {
+		return Diff{}, err
+	}
+
+	toAvro, err := to.ParseAvro()
+	if err != nil {
+		return Diff{}, err
+	}
+
+	var diff Diff
+	err = diffAvro(&diff, "", fromAvro, toAvro)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return diff, nil
+}
+
+// diffAvro appends to the diff the difference between two Avro documents. The
+// records are compared field by field, recursively for the fields being
+// records in both documents, the others as a whole.
+func diffAvro(diff *Diff, name string, from, to map[string]interface{}) error {
+	if from["type"] != "record" || to["type"] != "record" {
+		if !reflect.DeepEqual(from, to) {
+			diff.Changed = append(diff.Changed, FieldChange{Name: name, From: avroType(from), To: avroType(to)})
+		}
+		return nil
+	}
+
+	fromFields, err := avroFields(from)
+	if err != nil {
+		return err
+	}
+
+	toFields, err := avroFields(to)
+	if err != nil {
+		return err
+	}
+
+	fromTypes := make(map[string]interface{}, len(fromFields))
+	for _, field := range fromFields {
+		fromTypes[field.Name] = field.Type
+	}
+
+	toTypes := make(map[string]interface{}, len(toFields))
+	for _, field := range toFields {
+		toTypes[field.Name] = field.Type
+	}
+
+	for _, field := range fromFields {
+		if _, ok := toTypes[field.Name]; !ok {
+			diff.Removed = append(diff.Removed, DiffField{Name: fieldPath(name, field.Name), Type: field.Type})
+		}
+	}
+
+	for _, field := range toFields {
+		path := fieldPath(name, field.Name)
+
+		fromType, ok := fromTypes[field.Name]
+		if !ok {
+			diff.Added = append(diff.Added, DiffField{Name: path, Type: field.Type})
+			continue
+		}
+
+		fromRecord, fromIsObject := fromType.(map[string]interface{})
+		toRecord, toIsObject := field.Type.(map[string]interface{})
+		if fromIsObject && toIsObject {
+			err = diffAvro(diff, path, fromRecord, toRecord)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(fromType, field.Type) {
+			diff.Changed = append(diff.Changed, FieldChange{Name: path, From: fromType, To: field.Type})
+		}
+	}
+
+	return nil
+}
+
+// avroFields returns the fields of the Avro record, in their declaration
+// order.
+func avroFields(record map[string]interface{}) ([]DiffField, error) {
+	rawFields, ok := record["fields"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to diff the schemas: the record %v has no fields", record["name"])
+	}
+
+	fields := make([]DiffField, 0, len(rawFields))
+	for _, rawField := range rawFields {
+		field, ok := rawField.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failed to diff the schemas: unexpected field %v in the record %v", rawField, record["name"])
+		}
+
+		name, ok := field["name"].(string)
+		if !ok {
+			return nil, fmt.Errorf("failed to diff the schemas: unnamed field in the record %v", record["name"])
+		}
+
+		fields = append(fields, DiffField{Name: name, Type: field["type"]})
+	}
+
+	return fields, nil
+}
+
+// avroType returns the type of the Avro document, unwrapping the primitive
+// and union schemas wrapped by ParseAvro.
+func avroType(document map[string]interface{}) interface{} {
+	if len(document) == 1 {
+		if t, ok := document["type"]; ok {
+			return t
+		}
+	}
+
+	return document
+}
+
+func fieldPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
@@ -0,0 +1,162 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiffSchemas_with_an_added_field(t *testing.T) {
+	diff, err := DiffSchemas(
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}]}`},
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}, {"name": "email", "type": ["null", "string"], "default": null}]}`},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Added: []DiffField{{Name: "email", Type: []interface{}{"null", "string"}}},
+	}, diff)
+	assert.False(t, diff.IsEmpty())
+}
+
+func Test_DiffSchemas_with_a_removed_field(t *testing.T) {
+	diff, err := DiffSchemas(
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}, {"name": "email", "type": "string"}]}`},
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}]}`},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Removed: []DiffField{{Name: "email", Type: "string"}},
+	}, diff)
+}
+
+func Test_DiffSchemas_with_a_type_change(t *testing.T) {
+	diff, err := DiffSchemas(
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "int"}]}`},
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}]}`},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Changed: []FieldChange{{Name: "id", From: "int", To: "long"}},
+	}, diff)
+}
+
+func Test_DiffSchemas_with_a_nested_record(t *testing.T) {
+	diff, err := DiffSchemas(
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [
+			{"name": "address", "type": {"type": "record", "name": "address", "fields": [
+				{"name": "street", "type": "string"},
+				{"name": "zip", "type": "int"}
+			]}}
+		]}`},
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [
+			{"name": "address", "type": {"type": "record", "name": "address", "fields": [
+				{"name": "zip", "type": "string"},
+				{"name": "city", "type": "string"}
+			]}}
+		]}`},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Added:   []DiffField{{Name: "address.city", Type: "string"}},
+		Removed: []DiffField{{Name: "address.street", Type: "string"}},
+		Changed: []FieldChange{{Name: "address.zip", From: "int", To: "string"}},
+	}, diff)
+}
+
+func Test_DiffSchemas_with_the_same_fields(t *testing.T) {
+	diff, err := DiffSchemas(
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": [{"name": "id", "type": "long"}]}`},
+		&Schema{Schema: `{"type": "record", "name": "user", "doc": "A user", "fields": [{"name": "id", "type": "long"}]}`},
+	)
+
+	require.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+}
+
+func Test_DiffSchemas_with_primitives(t *testing.T) {
+	diff, err := DiffSchemas(&Schema{Schema: `"int"`}, &Schema{Schema: `"long"`})
+
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Changed: []FieldChange{{Name: "", From: "int", To: "long"}},
+	}, diff)
+}
+
+func Test_DiffSchemas_with_an_invalid_record(t *testing.T) {
+	_, err := DiffSchemas(
+		&Schema{Schema: `{"type": "record", "name": "user"}`},
+		&Schema{Schema: `{"type": "record", "name": "user", "fields": []}`},
+	)
+
+	assert.EqualError(t, err, "failed to diff the schemas: the record user has no fields")
+}
+
+func Test_DiffSchemas_with_a_non_avro_schema(t *testing.T) {
+	_, err := DiffSchemas(
+		&Schema{Schema: `{"type": "object"}`, SchemaType: SchemaTypeJSON},
+		&Schema{Schema: `"string"`},
+	)
+
+	assert.EqualError(t, err, "failed to parse the Avro schema: the schema type is JSON")
+}
+
+func Test_SchemaDiff(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.String() {
+		case "/subjects/test/versions/1":
+			body = `{"subject": "test", "version": 1, "id": 11, "schema": "{\"type\":\"record\",\"name\":\"user\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"}]}"}`
+		case "/subjects/test/versions/2":
+			body = `{"subject": "test", "version": 2, "id": 12, "schema": "{\"type\":\"record\",\"name\":\"user\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"},{\"name\":\"name\",\"type\":\"string\"}]}"}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	diff, err := client.SchemaDiff(context.Background(), "test", 1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Added: []DiffField{{Name: "name", Type: "string"}},
+	}, diff)
+}
+
+func Test_SchemaDiff_with_a_missing_version(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.String() {
+		case "/subjects/test/versions/1":
+			body = `{"subject": "test", "version": 1, "id": 11, "schema": "{\"type\":\"record\",\"name\":\"user\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"}]}"}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			body = `{"error_code": 40402, "message": "Version not found."}`
+		}
+
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	diff, err := client.SchemaDiff(context.Background(), "test", 1, 3)
+
+	assert.Empty(t, diff)
+	assert.True(t, IsVersionNotFound(err))
+}
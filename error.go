@@ -1,88 +1,434 @@
 package schemaregistry
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"strings"
 )
 
-// These numbers are used by the schema registry to communicate errors.
+// These numbers are used by the schema registry to communicate errors. Use
+// them with `HasErrorCode` to check for an error without a dedicated helper.
 const (
-	subjectNotFoundCode = 40401
-	versionNotFoundCode = 40402
-	schemaNotFoundCode  = 40403
+	SubjectNotFound                        = 40401
+	VersionNotFound                        = 40402
+	SchemaNotFound                         = 40403
+	SubjectSoftDeleted                     = 40404
+	SubjectNotSoftDeleted                  = 40405
+	SchemaVersionSoftDeleted               = 40406
+	SchemaVersionNotSoftDeleted            = 40407
+	SubjectLevelCompatibilityNotConfigured = 40408
+
+	IncompatibleSchema = 409
+
+	InvalidSchema             = 42201
+	InvalidVersion            = 42202
+	InvalidCompatibilityLevel = 42203
+	InvalidMode               = 42204
+	OperationNotPermitted     = 42205
+	ReferenceExists           = 42206
+
+	BackendStoreError       = 50001
+	OperationTimeout        = 50002
+	RequestForwardingFailed = 50003
 )
 
+// errorCodeNames describes the error codes, for the errors without message.
+var errorCodeNames = map[int]string{
+	SubjectNotFound:                        "subject not found",
+	VersionNotFound:                        "version not found",
+	SchemaNotFound:                         "schema not found",
+	SubjectSoftDeleted:                     "subject soft-deleted",
+	SubjectNotSoftDeleted:                  "subject not soft-deleted",
+	SchemaVersionSoftDeleted:               "schema version soft-deleted",
+	SchemaVersionNotSoftDeleted:            "schema version not soft-deleted",
+	SubjectLevelCompatibilityNotConfigured: "subject compatibility level not configured",
+	IncompatibleSchema:                     "incompatible schema",
+	InvalidSchema:                          "invalid schema",
+	InvalidVersion:                         "invalid version",
+	InvalidCompatibilityLevel:              "invalid compatibility level",
+	InvalidMode:                            "invalid mode",
+	OperationNotPermitted:                  "operation not permitted",
+	ReferenceExists:                        "reference exists",
+	BackendStoreError:                      "backend store error",
+	OperationTimeout:                       "operation timeout",
+	RequestForwardingFailed:                "request forwarding failed",
+}
+
+// maxErrorSnippetBytes is the maximum number of bytes of an invalid error body
+// kept in the error message.
+const maxErrorSnippetBytes = 256
+
+// ErrEmptyResponse is returned when the server answers with an empty body to a
+// request expecting a value.
+var ErrEmptyResponse = errors.New("empty response")
+
+// ErrSchemaTooLarge is returned when a schema read by
+// `RegisterNewSchemaFromReader` exceeds the size limit, look
+// `UsingMaxSchemaBytes`.
+var ErrSchemaTooLarge = errors.New("the schema exceeds the size limit")
+
 // ResourceError is being fired from all API calls when an error code is received.
 type ResourceError struct {
 	ErrorCode int    `json:"error_code"`
 	Method    string `json:"method,omitempty"`
 	URI       string `json:"uri,omitempty"`
 	Message   string `json:"message,omitempty"`
+	// StatusCode is the HTTP status of the response, zero for the errors not
+	// returned by a server.
+	StatusCode int `json:"-"`
+	// Details holds the fields of the error body other than the code and
+	// the message, some registries use them to describe the failure (e.g.
+	// the invalid field of a schema). It is nil if there are none.
+	Details map[string]interface{} `json:"-"`
 }
 
 // Error is used to implement the error interface.
+//
+// An error without message is described by the name of its code if known, by
+// the HTTP status otherwise.
 func (err ResourceError) Error() string {
-	return fmt.Sprintf("client: (%s: %s) failed with error code %d: %s",
-		err.Method, err.URI, err.ErrorCode, err.Message)
+	description := err.Message
+	if description == "" {
+		description = errorCodeNames[err.ErrorCode]
+	}
+	if description == "" {
+		description = http.StatusText(err.StatusCode)
+	}
+
+	msg := fmt.Sprintf("client: (%s: %s) failed with error code %d: %s",
+		err.Method, err.URI, err.ErrorCode, description)
+	if err.ErrorCode == 0 && err.StatusCode != 0 {
+		msg = fmt.Sprintf("client: (%s: %s) failed with HTTP status %d: %s",
+			err.Method, err.URI, err.StatusCode, description)
+	}
+
+	if len(err.Details) == 0 {
+		return msg
+	}
+
+	// nolint
+	// Error not possible here, the details have been decoded from JSON.
+	details, _ := json.Marshal(err.Details)
+
+	return fmt.Sprintf("%s %s", msg, details)
 }
 
-// IsSubjectNotFound checks the returned error to see if it is kind of a subject
-// not found  error code.
-func IsSubjectNotFound(err error) bool {
-	if err == nil {
-		return false
+// IncompatibleSchemaError is returned when a schema is refused on the client
+// side because it is incompatible with the subject versions.
+// It is also returned when the server refused a registration, wrapping its
+// error, with the `UsingVerboseIncompatibility` option.
+type IncompatibleSchemaError struct {
+	Subject string
+	// Messages explain the incompatibility, they are empty if the server
+	// doesn't support the verbose compatibility checks.
+	Messages []string
+	// Err is the error returned by the server, if any.
+	Err error
+}
+
+// Error is used to implement the error interface.
+func (err IncompatibleSchemaError) Error() string {
+	if len(err.Messages) == 0 {
+		return fmt.Sprintf("schema incompatible with the subject %s", err.Subject)
 	}
 
-	if resErr, ok := err.(ResourceError); ok {
-		return resErr.ErrorCode == subjectNotFoundCode
+	return fmt.Sprintf("schema incompatible with the subject %s: %s", err.Subject, strings.Join(err.Messages, "; "))
+}
+
+// Unwrap returns the error returned by the server, if any.
+func (err IncompatibleSchemaError) Unwrap() error {
+	return err.Err
+}
+
+// UnexpectedContentTypeError is returned when a successful response is an HTML
+// page instead of JSON, as the login page of an SSO-protected endpoint.
+type UnexpectedContentTypeError struct {
+	ContentType string
+	Method      string
+	URI         string
+}
+
+// Error is used to implement the error interface.
+func (err UnexpectedContentTypeError) Error() string {
+	return fmt.Sprintf("client: (%s: %s) unexpected content type %s: the base URL may not target a schema registry, or the request may not be authenticated",
+		err.Method, err.URI, err.ContentType)
+}
+
+// AuthError is returned when the server refuses the credentials of the
+// request, with a "401 Unauthorized" or a "403 Forbidden" status. It denotes a
+// misconfiguration, the request is not retried.
+type AuthError struct {
+	StatusCode int
+	Method     string
+	URI        string
+	// Message is the message of the server, if any.
+	Message string
+	// Err is the ResourceError sent by the server, nil if the body is not a
+	// JSON error.
+	Err error
+}
+
+// Error is used to implement the error interface.
+func (err AuthError) Error() string {
+	description := err.Message
+	if description == "" {
+		description = http.StatusText(err.StatusCode)
 	}
 
-	return false
+	return fmt.Sprintf("client: (%s: %s) authorization failed with HTTP status %d: %s",
+		err.Method, err.URI, err.StatusCode, description)
 }
 
-// IsVersionNotFound checks the returned error to see if it's related to a
-// version not found.
-func IsVersionNotFound(err error) bool {
-	if err == nil {
-		return false
+// Unwrap returns the ResourceError sent by the server, if any.
+func (err AuthError) Unwrap() error {
+	return err.Err
+}
+
+// isHTML tells if the content type is the one of an HTML page.
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// MultiError holds the errors of a batch operation per subject/version, the
+// version being zero for the operations made per subject. The errors.Is and
+// errors.As functions, and so the helpers like `IsSchemaNotFound`, look into
+// all the errors it holds.
+type MultiError map[SubjectVersion]error
+
+// Error is used to implement the error interface.
+func (errs MultiError) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, item := range errs.items() {
+		if item.Version == 0 {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", item.Subject, errs[item]))
+		} else {
+			msgs = append(msgs, fmt.Sprintf("%s/%d: %s", item.Subject, item.Version, errs[item]))
+		}
 	}
 
-	if resErr, ok := err.(ResourceError); ok {
-		return resErr.ErrorCode == versionNotFoundCode
+	return fmt.Sprintf("%d item(s) failed: %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// Is tells if any of the errors matches the target, see errors.Is.
+func (errs MultiError) Is(target error) bool {
+	for _, item := range errs.items() {
+		if errors.Is(errs[item], target) {
+			return true
+		}
 	}
 
 	return false
 }
 
+// As finds the first error, by subject and version, matching the target, see
+// errors.As.
+func (errs MultiError) As(target interface{}) bool {
+	for _, item := range errs.items() {
+		if errors.As(errs[item], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// items returns the failed subject/version pairs sorted.
+func (errs MultiError) items() []SubjectVersion {
+	items := make([]SubjectVersion, 0, len(errs))
+	for item := range errs {
+		items = append(items, item)
+	}
+
+	sortSubjectVersions(items)
+
+	return items
+}
+
+// IsSubjectNotFound checks the returned error to see if it is kind of a subject
+// not found  error code. The wrapped errors are checked too.
+func IsSubjectNotFound(err error) bool {
+	return HasErrorCode(err, SubjectNotFound)
+}
+
+// IsVersionNotFound checks the returned error to see if it's related to a
+// version not found. The wrapped errors are checked too.
+func IsVersionNotFound(err error) bool {
+	return HasErrorCode(err, VersionNotFound)
+}
+
 // IsSchemaNotFound checks the returned error to see if it is kind of a schema
-// not found error code.
+// not found error code. The wrapped errors are checked too.
 func IsSchemaNotFound(err error) bool {
-	if err == nil {
+	return HasErrorCode(err, SchemaNotFound)
+}
+
+// IsIncompatibleSchema checks the returned error to see if the schema have
+// been rejected because it is incompatible with the subject versions, by the
+// server or by the client (see `IncompatibleSchemaError`). The wrapped errors
+// are checked too.
+func IsIncompatibleSchema(err error) bool {
+	var incompatibleErr IncompatibleSchemaError
+
+	return HasErrorCode(err, IncompatibleSchema) || errors.As(err, &incompatibleErr)
+}
+
+// IsAuthError checks the returned error to see if the server refused the
+// credentials of the request (see `AuthError`). The wrapped errors are checked
+// too.
+func IsAuthError(err error) bool {
+	var authErr AuthError
+
+	return errors.As(err, &authErr)
+}
+
+// IsInvalidSchema checks the returned error to see if the schema, or its
+// version, have been rejected because they are invalid. The wrapped errors are
+// checked too.
+func IsInvalidSchema(err error) bool {
+	return HasErrorCode(err, InvalidSchema) || HasErrorCode(err, InvalidVersion)
+}
+
+// HasErrorCode checks if err, or any error it wraps, is a ResourceError with
+// the given code.
+func HasErrorCode(err error, code int) bool {
+	// A MultiError can hold several ResourceError, errors.As would only
+	// return the first one.
+	var multiErr MultiError
+	if errors.As(err, &multiErr) {
+		for _, itemErr := range multiErr {
+			if HasErrorCode(itemErr, code) {
+				return true
+			}
+		}
+
 		return false
 	}
 
-	if resErr, ok := err.(ResourceError); ok {
-		return resErr.ErrorCode == schemaNotFoundCode
+	var resErr ResourceError
+
+	if errors.As(err, &resErr) {
+		return resErr.ErrorCode == code
 	}
 
 	return false
 }
 
-func parseResponseError(req *http.Request, res *http.Response) error {
-	if res.StatusCode == 200 {
+func parseResponseError(req *http.Request, res *http.Response, maxBytes int64) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
 		return nil
 	}
 
-	var resErr ResourceError
+	rawBody, err := readErrorBody(res, maxBytes)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return parseAuthError(req, res, rawBody)
+	}
 
-	err := json.NewDecoder(res.Body).Decode(&resErr)
+	var resErr ResourceError
+	err = json.Unmarshal(rawBody, &resErr)
 	if err != nil {
-		return fmt.Errorf("failed to decode the response: %s", err)
+		return fmt.Errorf("failed to decode the response: %s (status %d: %q)", err, res.StatusCode, errorSnippet(rawBody))
 	}
 
+	resErr.Details = errorDetails(rawBody)
+
 	resErr.URI = req.URL.String()
 	resErr.Method = req.Method
+	resErr.StatusCode = res.StatusCode
 
 	return resErr
 }
+
+// parseAuthError returns the AuthError of the response. The body is often
+// empty or a plain text, it is kept as the message if it is not a JSON error.
+func parseAuthError(req *http.Request, res *http.Response, rawBody []byte) error {
+	authErr := AuthError{
+		StatusCode: res.StatusCode,
+		Method:     req.Method,
+		URI:        req.URL.String(),
+		Message:    errorSnippet(rawBody),
+	}
+
+	var resErr ResourceError
+	if json.Unmarshal(rawBody, &resErr) == nil {
+		resErr.Details = errorDetails(rawBody)
+		resErr.URI = authErr.URI
+		resErr.Method = authErr.Method
+		resErr.StatusCode = authErr.StatusCode
+
+		authErr.Message = resErr.Message
+		authErr.Err = resErr
+	}
+
+	return authErr
+}
+
+// readErrorBody reads the body of an error response. The proxies in front of
+// the registry can send a compressed error page even if it was not requested,
+// the body is decompressed in that case, up to maxBytes.
+func readErrorBody(res *http.Response, maxBytes int64) ([]byte, error) {
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return ioutil.ReadAll(res.Body)
+	}
+
+	reader, err := gzip.NewReader(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the response: %s", err)
+	}
+	defer reader.Close()
+
+	// One more byte is read in order to detect the bodies over the limit, the
+	// limit of the compressed body doesn't bound the decompressed one.
+	rawBody, err := ioutil.ReadAll(io.LimitReader(reader, overLimit(maxBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the response: %s", err)
+	}
+
+	if int64(len(rawBody)) > maxBytes {
+		return nil, fmt.Errorf("the response body exceeds the limit of %d bytes", maxBytes)
+	}
+
+	return rawBody, nil
+}
+
+// errorSnippet returns the beginning of an error body which is not a valid
+// JSON, to give a hint about its origin (e.g. the error page of a proxy).
+func errorSnippet(rawBody []byte) string {
+	snippet := strings.TrimSpace(string(rawBody))
+	if len(snippet) > maxErrorSnippetBytes {
+		snippet = snippet[:maxErrorSnippetBytes] + "..."
+	}
+
+	return snippet
+}
+
+// errorDetails returns the fields of an error body not decoded in the
+// ResourceError fields, or nil if there are none.
+func errorDetails(rawBody []byte) map[string]interface{} {
+	var details map[string]interface{}
+
+	// The body is known to be a valid JSON object at this point, the
+	// error can only come from an unexpected top level type.
+	if json.Unmarshal(rawBody, &details) != nil {
+		return nil
+	}
+
+	delete(details, "error_code")
+	delete(details, "message")
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	return details
+}
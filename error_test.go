@@ -1,7 +1,10 @@
 package schemaregistry
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,7 +12,7 @@ import (
 
 func Test_IsSubjectNotFound(t *testing.T) {
 	err := ResourceError{
-		ErrorCode: subjectNotFoundCode,
+		ErrorCode: SubjectNotFound,
 		Method:    "GET",
 		URI:       "some-uri",
 		Message:   "some-error",
@@ -19,6 +22,17 @@ func Test_IsSubjectNotFound(t *testing.T) {
 	assert.False(t, IsSchemaNotFound(err))
 }
 
+func Test_IsSubjectNotFound_with_a_wrapped_error(t *testing.T) {
+	err := fmt.Errorf("some-context: %w", ResourceError{
+		ErrorCode: SubjectNotFound,
+		Method:    "GET",
+		URI:       "some-uri",
+		Message:   "some-error",
+	})
+
+	assert.True(t, IsSubjectNotFound(err))
+}
+
 func Test_IsSubjectNotFound_with_no_error(t *testing.T) {
 	assert.False(t, IsSubjectNotFound(nil))
 }
@@ -29,7 +43,7 @@ func Test_IsSubjectNotFound_with_system_error(t *testing.T) {
 
 func Test_IsVersionNotFound(t *testing.T) {
 	err := ResourceError{
-		ErrorCode: versionNotFoundCode,
+		ErrorCode: VersionNotFound,
 		Method:    "GET",
 		URI:       "some-uri",
 		Message:   "some-error",
@@ -39,6 +53,17 @@ func Test_IsVersionNotFound(t *testing.T) {
 	assert.False(t, IsSubjectNotFound(err))
 }
 
+func Test_IsVersionNotFound_with_a_wrapped_error(t *testing.T) {
+	err := fmt.Errorf("some-context: %w", ResourceError{
+		ErrorCode: VersionNotFound,
+		Method:    "GET",
+		URI:       "some-uri",
+		Message:   "some-error",
+	})
+
+	assert.True(t, IsVersionNotFound(err))
+}
+
 func Test_IsVersionNotFound_with_no_error(t *testing.T) {
 	assert.False(t, IsVersionNotFound(nil))
 }
@@ -49,7 +74,7 @@ func Test_IsVersionNotFound_with_system_error(t *testing.T) {
 
 func Test_IsSchemaNotFound(t *testing.T) {
 	err := ResourceError{
-		ErrorCode: schemaNotFoundCode,
+		ErrorCode: SchemaNotFound,
 		Method:    "GET",
 		URI:       "some-uri",
 		Message:   "some-error",
@@ -59,6 +84,17 @@ func Test_IsSchemaNotFound(t *testing.T) {
 	assert.False(t, IsSubjectNotFound(err))
 }
 
+func Test_IsSchemaNotFound_with_a_wrapped_error(t *testing.T) {
+	err := fmt.Errorf("some-context: %w", ResourceError{
+		ErrorCode: SchemaNotFound,
+		Method:    "GET",
+		URI:       "some-uri",
+		Message:   "some-error",
+	})
+
+	assert.True(t, IsSchemaNotFound(err))
+}
+
 func Test_IsSchemaNotFound_with_no_error(t *testing.T) {
 	assert.False(t, IsSchemaNotFound(nil))
 }
@@ -67,9 +103,83 @@ func Test_IsSchemaNotFound_with_system_error(t *testing.T) {
 	assert.False(t, IsSchemaNotFound(fmt.Errorf("some-error")))
 }
 
+func Test_IsIncompatibleSchema(t *testing.T) {
+	err := ResourceError{
+		ErrorCode: IncompatibleSchema,
+		Method:    "POST",
+		URI:       "some-uri",
+		Message:   "some-error",
+	}
+
+	assert.True(t, IsIncompatibleSchema(err))
+	assert.False(t, IsInvalidSchema(err))
+}
+
+func Test_IsIncompatibleSchema_with_a_wrapped_error(t *testing.T) {
+	err := fmt.Errorf("some-context: %w", ResourceError{ErrorCode: IncompatibleSchema})
+
+	assert.True(t, IsIncompatibleSchema(err))
+}
+
+func Test_IsIncompatibleSchema_with_a_client_side_error(t *testing.T) {
+	err := IncompatibleSchemaError{Subject: "test", Messages: []string{"field removed"}}
+
+	assert.True(t, IsIncompatibleSchema(err))
+	assert.EqualError(t, err, "schema incompatible with the subject test: field removed")
+}
+
+func Test_IsIncompatibleSchema_with_no_error(t *testing.T) {
+	assert.False(t, IsIncompatibleSchema(nil))
+}
+
+func Test_IsInvalidSchema(t *testing.T) {
+	for _, code := range []int{InvalidSchema, InvalidVersion} {
+		err := ResourceError{
+			ErrorCode: code,
+			Method:    "POST",
+			URI:       "some-uri",
+			Message:   "some-error",
+		}
+
+		assert.True(t, IsInvalidSchema(err), code)
+		assert.False(t, IsIncompatibleSchema(err), code)
+	}
+}
+
+func Test_IsInvalidSchema_with_a_wrapped_error(t *testing.T) {
+	err := fmt.Errorf("some-context: %w", ResourceError{ErrorCode: InvalidSchema})
+
+	assert.True(t, IsInvalidSchema(err))
+}
+
+func Test_IsInvalidSchema_with_system_error(t *testing.T) {
+	assert.False(t, IsInvalidSchema(fmt.Errorf("some-error")))
+}
+
+func Test_HasErrorCode(t *testing.T) {
+	err := ResourceError{
+		ErrorCode: ReferenceExists,
+		Method:    "DELETE",
+		URI:       "some-uri",
+		Message:   "some-error",
+	}
+
+	assert.True(t, HasErrorCode(err, ReferenceExists))
+	assert.True(t, HasErrorCode(fmt.Errorf("some-context: %w", err), ReferenceExists))
+	assert.False(t, HasErrorCode(err, OperationNotPermitted))
+}
+
+func Test_HasErrorCode_with_no_error(t *testing.T) {
+	assert.False(t, HasErrorCode(nil, SubjectNotFound))
+}
+
+func Test_HasErrorCode_with_system_error(t *testing.T) {
+	assert.False(t, HasErrorCode(fmt.Errorf("some-error"), SubjectNotFound))
+}
+
 func Test_ResourceError_Error_format(t *testing.T) {
 	err := ResourceError{
-		ErrorCode: schemaNotFoundCode,
+		ErrorCode: SchemaNotFound,
 		Method:    "GET",
 		URI:       "some-uri",
 		Message:   "some-error",
@@ -77,3 +187,127 @@ func Test_ResourceError_Error_format(t *testing.T) {
 
 	assert.Equal(t, "client: (GET: some-uri) failed with error code 40403: some-error", err.Error())
 }
+
+func Test_ResourceError_Error_format_with_details(t *testing.T) {
+	err := ResourceError{
+		ErrorCode: InvalidSchema,
+		Method:    "POST",
+		URI:       "some-uri",
+		Message:   "some-error",
+		Details:   map[string]interface{}{"field": "name"},
+	}
+
+	assert.Equal(t, `client: (POST: some-uri) failed with error code 42201: some-error {"field":"name"}`, err.Error())
+}
+
+func Test_ResourceError_Error_format_with_a_known_code_without_message(t *testing.T) {
+	err := ResourceError{
+		ErrorCode:  SubjectNotFound,
+		Method:     "GET",
+		URI:        "some-uri",
+		StatusCode: http.StatusNotFound,
+	}
+
+	assert.Equal(t, "client: (GET: some-uri) failed with error code 40401: subject not found", err.Error())
+}
+
+func Test_ResourceError_Error_format_with_an_unknown_code_without_message(t *testing.T) {
+	err := ResourceError{
+		ErrorCode:  40499,
+		Method:     "GET",
+		URI:        "some-uri",
+		StatusCode: http.StatusNotFound,
+	}
+
+	assert.Equal(t, "client: (GET: some-uri) failed with error code 40499: Not Found", err.Error())
+}
+
+func Test_ResourceError_Error_format_without_code_nor_message(t *testing.T) {
+	err := ResourceError{
+		Method:     "GET",
+		URI:        "some-uri",
+		StatusCode: http.StatusBadGateway,
+	}
+
+	assert.Equal(t, "client: (GET: some-uri) failed with HTTP status 502: Bad Gateway", err.Error())
+}
+
+func Test_UnexpectedContentTypeError_Error_format(t *testing.T) {
+	err := UnexpectedContentTypeError{
+		ContentType: "text/html",
+		Method:      "GET",
+		URI:         "some-uri",
+	}
+
+	assert.EqualError(t, err, "client: (GET: some-uri) unexpected content type text/html: the base URL may not target a schema registry, or the request may not be authenticated")
+}
+
+func Test_AuthError_Error_format(t *testing.T) {
+	err := AuthError{
+		StatusCode: http.StatusForbidden,
+		Method:     "GET",
+		URI:        "some-uri",
+		Message:    "User cannot access the resource",
+	}
+
+	assert.EqualError(t, err, "client: (GET: some-uri) authorization failed with HTTP status 403: User cannot access the resource")
+}
+
+func Test_AuthError_Error_format_without_message(t *testing.T) {
+	err := AuthError{
+		StatusCode: http.StatusUnauthorized,
+		Method:     "GET",
+		URI:        "some-uri",
+	}
+
+	assert.EqualError(t, err, "client: (GET: some-uri) authorization failed with HTTP status 401: Unauthorized")
+}
+
+func Test_IsAuthError(t *testing.T) {
+	assert.True(t, IsAuthError(AuthError{StatusCode: http.StatusUnauthorized}))
+	assert.True(t, IsAuthError(fmt.Errorf("wrapped: %w", AuthError{StatusCode: http.StatusForbidden})))
+	assert.True(t, IsAuthError(MultiError{SubjectVersion{Subject: "foo"}: AuthError{StatusCode: http.StatusForbidden}}))
+	assert.False(t, IsAuthError(ResourceError{ErrorCode: 40401}))
+	assert.False(t, IsAuthError(nil))
+}
+
+func Test_MultiError_Error_format(t *testing.T) {
+	err := MultiError{
+		SubjectVersion{Subject: "foo", Version: 2}: fmt.Errorf("some-error"),
+		SubjectVersion{Subject: "bar"}:             fmt.Errorf("other-error"),
+		SubjectVersion{Subject: "foo", Version: 1}: fmt.Errorf("another-error"),
+	}
+
+	assert.EqualError(t, err, "3 item(s) failed: bar: other-error; foo/1: another-error; foo/2: some-error")
+}
+
+func Test_MultiError_with_a_contained_error(t *testing.T) {
+	var err error = MultiError{
+		SubjectVersion{Subject: "foo", Version: 1}: ResourceError{ErrorCode: BackendStoreError},
+		SubjectVersion{Subject: "foo", Version: 2}: fmt.Errorf("wrapped: %w", ResourceError{ErrorCode: SchemaNotFound}),
+	}
+
+	assert.True(t, IsSchemaNotFound(err))
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+	assert.False(t, IsSubjectNotFound(err))
+
+	var resErr ResourceError
+	assert.True(t, errors.As(err, &resErr))
+	assert.Equal(t, BackendStoreError, resErr.ErrorCode)
+}
+
+func Test_MultiError_with_errors_Is(t *testing.T) {
+	err := fmt.Errorf("batch: %w", MultiError{
+		SubjectVersion{Subject: "foo"}: fmt.Errorf("wrapped: %w", ErrEmptyResponse),
+	})
+
+	assert.True(t, errors.Is(err, ErrEmptyResponse))
+	assert.False(t, errors.Is(err, ErrNoCompatibilityChecker))
+	assert.True(t, IsIncompatibleSchema(MultiError{SubjectVersion{Subject: "foo"}: IncompatibleSchemaError{Subject: "foo"}}))
+}
+
+func Test_errorSnippet_truncates_long_bodies(t *testing.T) {
+	snippet := errorSnippet([]byte(strings.Repeat("a", maxErrorSnippetBytes+10)))
+
+	assert.Equal(t, strings.Repeat("a", maxErrorSnippetBytes)+"...", snippet)
+}
@@ -0,0 +1,58 @@
+package schemaregistry
+
+import "net/http"
+
+// maxETagCacheEntries is the number of responses kept by the ETag cache, the
+// least recently used ones are evicted past it.
+const maxETagCacheEntries = 1000
+
+// UsingETagCache enables the conditional reads: the responses of the GET
+// requests with an ETag header are kept, and sent again with If-None-Match. A
+// "304 Not Modified" response is then answered with the kept response without
+// transferring it again, reducing the bandwidth of the schemas polled often
+// with GetLatestSchema or GetSchemaByID.
+//
+// The last 1000 responses used are kept, so the memory used is bounded even
+// when reading many schemas.
+//
+// It only has an effect with the servers supporting the conditional requests.
+func UsingETagCache() Option {
+	return func(c *Client) {
+		c.etagCache = newETagCache(maxETagCacheEntries)
+	}
+}
+
+type etagCacheEntry struct {
+	etag string
+	body []byte
+}
+
+// etagCache holds the last response body with an ETag per URL, for the most
+// recently used URLs. It is safe for concurrent use.
+type etagCache struct {
+	lru *lruCache
+}
+
+func newETagCache(maxEntries int) *etagCache {
+	return &etagCache{lru: newLRUCache(maxEntries)}
+}
+
+func (ec *etagCache) get(url string) (etagCacheEntry, bool) {
+	entry, ok := ec.lru.get(url)
+	if !ok {
+		return etagCacheEntry{}, false
+	}
+
+	return entry.(etagCacheEntry), true
+}
+
+// store keeps the body if the response has an ETag, evicting the least
+// recently used entry if the cache is full.
+func (ec *etagCache) store(url string, res *http.Response, body []byte) {
+	etag := res.Header.Get("ETag")
+	if etag == "" {
+		return
+	}
+
+	ec.lru.store(url, etagCacheEntry{etag: etag, body: body})
+}
@@ -0,0 +1,160 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetLatestSchema_with_an_etag_cache(t *testing.T) {
+	var fullResponses int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		atomic.AddInt32(&fullResponses, 1)
+
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingETagCache())
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		schema, err := client.GetLatestSchema(context.Background(), "test")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{Subject: "test", Version: 1, ID: 2, Schema: `"string"`}, schema)
+	}
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fullResponses))
+}
+
+func Test_GetSchemaByID_with_an_etag_cache(t *testing.T) {
+	var fullResponses int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		atomic.AddInt32(&fullResponses, 1)
+
+		w.Header().Set("ETag", `"abc"`)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingETagCache())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		schema, err := client.GetSchemaByID(context.Background(), 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `"string"`, schema)
+	}
+
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fullResponses))
+}
+
+func Test_GetLatestSchema_with_an_etag_cache_and_no_etag(t *testing.T) {
+	var fullResponses int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fullResponses, 1)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingETagCache())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetLatestSchema(context.Background(), "test")
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&fullResponses))
+}
+
+func Test_GetLatestSchema_without_etag_cache(t *testing.T) {
+	var fullResponses int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		atomic.AddInt32(&fullResponses, 1)
+
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "version": 1, "id": 2, "schema": "\"string\""}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetLatestSchema(context.Background(), "test")
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 2, atomic.LoadInt32(&fullResponses))
+}
+
+func Test_etagCache_evicts_the_least_recently_used_entry(t *testing.T) {
+	cache := newETagCache(2)
+	res := &http.Response{Header: http.Header{"Etag": {`"v1"`}}}
+
+	cache.store("a", res, []byte("a"))
+	cache.store("b", res, []byte("b"))
+
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	cache.store("c", res, []byte("c"))
+
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+
+	entry, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), entry.body)
+
+	entry, ok = cache.get("c")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("c"), entry.body)
+}
+
+func Test_etagCache_replaces_an_entry(t *testing.T) {
+	cache := newETagCache(2)
+
+	cache.store("a", &http.Response{Header: http.Header{"Etag": {`"v1"`}}}, []byte("v1"))
+	cache.store("a", &http.Response{Header: http.Header{"Etag": {`"v2"`}}}, []byte("v2"))
+	cache.store("b", &http.Response{Header: http.Header{"Etag": {`"v1"`}}}, []byte("b"))
+
+	entry, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, `"v2"`, entry.etag)
+	assert.Equal(t, []byte("v2"), entry.body)
+	assert.Equal(t, 2, cache.lru.len())
+}
@@ -0,0 +1,177 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ExportSchemas writes all the versions of all the subjects to w, as a stream
+// of JSON Schema objects separated by newlines. The schemas are fetched and
+// written one by one, the registry is never held in memory.
+//
+// The stream can be imported as is by `ImportSchemas`: the versions of a
+// subject are written by ascending version, and the versions referenced by a
+// schema are written before it.
+//
+// The soft deleted versions are not exported.
+func (c *Client) ExportSchemas(ctx context.Context, w io.Writer) error {
+	ctx = withOperation(ctx, "ExportSchemas")
+
+	subjects, err := c.Subjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	exporter := &schemaExporter{
+		client:   c,
+		encoder:  json.NewEncoder(w),
+		versions: map[string][]int{},
+		written:  map[SubjectVersion]bool{},
+	}
+
+	for _, subject := range subjects {
+		err = exporter.exportSubject(ctx, subject, LatestVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schemaExporter writes the versions of the subjects, their references first.
+// Only the versions lists and the written versions are kept in memory.
+type schemaExporter struct {
+	client   *Client
+	encoder  *json.Encoder
+	versions map[string][]int
+	written  map[SubjectVersion]bool
+}
+
+// exportSubject writes the versions of the subject up to maxVersion, or all
+// of them if it is LatestVersion, skipping the ones already written.
+func (e *schemaExporter) exportSubject(ctx context.Context, subject string, maxVersion int) error {
+	versions, ok := e.versions[subject]
+	if !ok {
+		var err error
+		versions, err = e.client.Versions(ctx, subject)
+		if err != nil {
+			return err
+		}
+
+		e.versions[subject] = versions
+	}
+
+	for _, version := range versions {
+		if maxVersion != LatestVersion && version > maxVersion {
+			return nil
+		}
+
+		err := e.exportVersion(ctx, subject, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportVersion writes the version of the subject after the versions it
+// references, if it has not already been written.
+func (e *schemaExporter) exportVersion(ctx context.Context, subject string, version int) error {
+	key := SubjectVersion{Subject: subject, Version: version}
+	if e.written[key] {
+		return nil
+	}
+
+	// The version is marked first so a reference cycle, refused by the
+	// registry anyway, can't loop forever.
+	e.written[key] = true
+
+	schema, err := e.client.GetSchemaBySubjectAndVersion(ctx, subject, version)
+	if err != nil {
+		return err
+	}
+
+	for _, reference := range schema.References {
+		err = e.exportSubject(ctx, reference.Subject, reference.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = e.encoder.Encode(schema)
+	if err != nil {
+		return fmt.Errorf("failed to write the schema %s/%d: %s", subject, version, err)
+	}
+
+	return nil
+}
+
+// ImportSchemas registers the schemas read from r, written by
+// `ExportSchemas`, keeping their subjects, versions and ids. The schemas are
+// imported in the order of the stream, so the referenced schemas must come
+// before the ones referencing them, as `ExportSchemas` writes them.
+//
+// The registry must be in the IMPORT mode, see `ImportSchema`.
+func (c *Client) ImportSchemas(ctx context.Context, r io.Reader) error {
+	ctx = withOperation(ctx, "ImportSchemas")
+
+	decoder := json.NewDecoder(r)
+
+	for {
+		var schema Schema
+
+		err := decoder.Decode(&schema)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read the schema: %s", err)
+		}
+
+		_, err = c.importSchema(ctx, &schema)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ImportedSchema is a version of a subject imported by
+// `ImportSubjectHistory`.
+type ImportedSchema struct {
+	Version int
+	ID      int
+	Schema  string
+}
+
+// ImportSubjectHistory registers the versions of a subject keeping their
+// versions and ids. They are imported by ascending version, whatever their
+// order in schemas, as the registry requires. The import stops at the first
+// version refused, the returned MultiError tells which one, and the previous
+// ones stay imported.
+//
+// The registry must be in the IMPORT mode, see `ImportSchema`.
+func (c *Client) ImportSubjectHistory(ctx context.Context, subject string, schemas []ImportedSchema) error {
+	ctx = withOperation(ctx, "ImportSubjectHistory")
+
+	ordered := make([]ImportedSchema, len(schemas))
+	copy(ordered, schemas)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Version < ordered[j].Version
+	})
+
+	for _, imported := range ordered {
+		_, err := c.ImportSchema(ctx, subject, imported.Schema, imported.Version, imported.ID)
+		if err != nil {
+			return MultiError{SubjectVersion{Subject: subject, Version: imported.Version}: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExportSchemas_and_ImportSchemas_round_trip(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.String() {
+		case "/subjects":
+			_, err = w.Write([]byte(`["common", "test"]`))
+		case "/subjects/common/versions":
+			_, err = w.Write([]byte(`[1]`))
+		case "/subjects/test/versions":
+			_, err = w.Write([]byte(`[1, 2]`))
+		case "/subjects/common/versions/1":
+			_, err = w.Write([]byte(`{"subject": "common", "version": 1, "id": 1, "schemaType": "PROTOBUF", "schema": "message Common {}"}`))
+		case "/subjects/test/versions/1":
+			_, err = w.Write([]byte(`{
+				"subject": "test",
+				"version": 1,
+				"id": 2,
+				"schema": "\"string\"",
+				"metadata": {"properties": {"owner": "team-a"}},
+				"ruleSet": {"domainRules": [{"name": "notEmpty", "kind": "CONDITION", "type": "CEL", "expr": "message != ''"}]}
+			}`))
+		case "/subjects/test/versions/2":
+			_, err = w.Write([]byte(`{
+				"subject": "test",
+				"version": 2,
+				"id": 3,
+				"schemaType": "PROTOBUF",
+				"schema": "import \"common.proto\";",
+				"references": [{"name": "common.proto", "subject": "common", "version": 1}]
+			}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer source.Close()
+
+	var (
+		lock     sync.Mutex
+		imported []string
+	)
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		raw, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		lock.Lock()
+		imported = append(imported, r.URL.String()+" "+string(raw))
+		lock.Unlock()
+
+		_, err = w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer target.Close()
+
+	sourceClient, err := NewClient(source.URL)
+	require.NoError(t, err)
+
+	targetClient, err := NewClient(target.URL)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = sourceClient.ExportSchemas(context.Background(), &buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+	err = targetClient.ImportSchemas(context.Background(), &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`/subjects/common/versions {"id":1,"schema":"message Common {}","schemaType":"PROTOBUF","version":1}`,
+		`/subjects/test/versions {"id":2,"metadata":{"properties":{"owner":"team-a"}},"ruleSet":{"domainRules":[{"expr":"message != ''","kind":"CONDITION","name":"notEmpty","type":"CEL"}]},"schema":"\"string\"","version":1}`,
+		`/subjects/test/versions {"id":3,"references":[{"name":"common.proto","subject":"common","version":1}],"schema":"import \"common.proto\";","schemaType":"PROTOBUF","version":2}`,
+	}, imported)
+}
+
+func Test_ExportSchemas_and_ImportSchemas_round_trip_with_a_later_reference(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		requests = map[string]int{}
+	)
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests[r.URL.String()]++
+		lock.Unlock()
+
+		var err error
+		switch r.URL.String() {
+		case "/subjects":
+			_, err = w.Write([]byte(`["order", "z-common"]`))
+		case "/subjects/order/versions":
+			_, err = w.Write([]byte(`[1]`))
+		case "/subjects/z-common/versions":
+			_, err = w.Write([]byte(`[1, 2, 3]`))
+		case "/subjects/order/versions/1":
+			_, err = w.Write([]byte(`{
+				"subject": "order",
+				"version": 1,
+				"id": 4,
+				"schemaType": "PROTOBUF",
+				"schema": "import \"common.proto\";",
+				"references": [{"name": "common.proto", "subject": "z-common", "version": 2}]
+			}`))
+		case "/subjects/z-common/versions/1", "/subjects/z-common/versions/2", "/subjects/z-common/versions/3":
+			version := strings.TrimPrefix(r.URL.String(), "/subjects/z-common/versions/")
+			_, err = w.Write([]byte(`{"subject": "z-common", "version": ` + version + `, "id": ` + version + `, "schemaType": "PROTOBUF", "schema": "message Common {}"}`))
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+		require.NoError(t, err)
+	}))
+	defer source.Close()
+
+	var imported []string
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Version int `json:"version"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		lock.Lock()
+		imported = append(imported, fmt.Sprintf("%s/%d", r.URL.Path, body.Version))
+		lock.Unlock()
+
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer target.Close()
+
+	sourceClient, err := NewClient(source.URL)
+	require.NoError(t, err)
+
+	targetClient, err := NewClient(target.URL)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = sourceClient.ExportSchemas(context.Background(), &buf)
+	require.NoError(t, err)
+
+	err = targetClient.ImportSchemas(context.Background(), &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/subjects/z-common/versions/1",
+		"/subjects/z-common/versions/2",
+		"/subjects/order/versions/1",
+		"/subjects/z-common/versions/3",
+	}, imported)
+
+	for path, count := range requests {
+		assert.Equal(t, 1, count, path)
+	}
+}
+
+func Test_ExportSchemas_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = client.ExportSchemas(context.Background(), &buf)
+
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+	assert.Empty(t, buf.String())
+}
+
+func Test_ImportSchemas_with_an_invalid_stream(t *testing.T) {
+	client, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	err = client.ImportSchemas(context.Background(), strings.NewReader(`{"subject": `))
+
+	assert.EqualError(t, err, "failed to read the schema: unexpected EOF")
+}
+
+func Test_ImportSubjectHistory_in_order(t *testing.T) {
+	var imported []int
+	var lock sync.Mutex
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		var body struct {
+			Version int `json:"version"`
+			ID      int `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		lock.Lock()
+		imported = append(imported, body.Version)
+		lock.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]int{"id": body.ID}))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.ImportSubjectHistory(context.Background(), "test", []ImportedSchema{
+		{Version: 3, ID: 30, Schema: `{"type": "long"}`},
+		{Version: 1, ID: 10, Schema: `{"type": "string"}`},
+		{Version: 2, ID: 20, Schema: `{"type": "int"}`},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, imported)
+}
+
+func Test_ImportSubjectHistory_with_a_refused_version(t *testing.T) {
+	var imported []int
+	var lock sync.Mutex
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test/versions", r.URL.String())
+
+		var body struct {
+			Version int `json:"version"`
+			ID      int `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if body.Version == 2 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, err := w.Write([]byte(`{"error_code": 42205, "message": "Subject test is not in import mode"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		lock.Lock()
+		imported = append(imported, body.Version)
+		lock.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]int{"id": body.ID}))
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.ImportSubjectHistory(context.Background(), "test", []ImportedSchema{
+		{Version: 1, ID: 10, Schema: `{"type": "string"}`},
+		{Version: 2, ID: 20, Schema: `{"type": "int"}`},
+		{Version: 3, ID: 30, Schema: `{"type": "long"}`},
+	})
+
+	assert.True(t, HasErrorCode(err, OperationNotPermitted))
+
+	var multiErr MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Contains(t, multiErr, SubjectVersion{Subject: "test", Version: 2})
+	assert.Equal(t, []int{1}, imported)
+}
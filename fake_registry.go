@@ -0,0 +1,365 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// FakeRegistry is an in-memory implementation of Registry for the integration
+// tests.
+//
+// Unlike ClientMock it behaves like a real registry: the schemas get
+// incrementing IDs shared between the subjects, the versions increment per
+// subject and the deleted versions are no longer returned. A FakeRegistry is
+// safe for concurrent use.
+type FakeRegistry struct {
+	// CompatibilityChecker tells if the schema is compatible with an existing
+	// one. It is used by SchemaCompatibleWith and RegisterNewSchema and all
+	// the schemas are compatible if nil.
+	CompatibilityChecker func(schema string, existing string) bool
+
+	lock     sync.Mutex
+	ids      map[string]int
+	schemas  map[int]string
+	subjects map[string][]*fakeVersion
+	global   Config
+	configs  map[string]Config
+}
+
+type fakeVersion struct {
+	version int
+	id      int
+	deleted bool
+}
+
+// NewFakeRegistry instantiate a new empty FakeRegistry with the BACKWARD global
+// compatibility.
+func NewFakeRegistry() *FakeRegistry {
+	return &FakeRegistry{
+		ids:      map[string]int{},
+		schemas:  map[int]string{},
+		subjects: map[string][]*fakeVersion{},
+		global:   Config{Compatibility: Backward},
+		configs:  map[string]Config{},
+	}
+}
+
+// GetSchemaByID returns the schema registered with the id.
+func (f *FakeRegistry) GetSchemaByID(ctx context.Context, subjectID int) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	schema, ok := f.schemas[subjectID]
+	if !ok {
+		return "", fakeError(SchemaNotFound, "Schema %d not found", subjectID)
+	}
+
+	return schema, nil
+}
+
+// Subjects returns the sorted list of the subjects with at least one version.
+func (f *FakeRegistry) Subjects(ctx context.Context) (subjects []string, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	subjects = []string{}
+	for subject := range f.subjects {
+		if len(f.activeVersions(subject)) > 0 {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	sort.Strings(subjects)
+
+	return subjects, nil
+}
+
+// Versions returns the versions of the subject not deleted.
+func (f *FakeRegistry) Versions(ctx context.Context, subject string) (versions []int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	active := f.activeVersions(subject)
+	if len(active) == 0 {
+		return nil, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	for _, v := range active {
+		versions = append(versions, v.version)
+	}
+
+	return versions, nil
+}
+
+// DeleteSubject deletes all the versions of the subject and returns them. The
+// options are ignored, nothing is protected from the deletion.
+func (f *FakeRegistry) DeleteSubject(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (versions []int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	active := f.activeVersions(subject)
+	if len(active) == 0 {
+		return nil, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	for _, v := range active {
+		v.deleted = true
+		versions = append(versions, v.version)
+	}
+
+	if permanent {
+		delete(f.subjects, subject)
+		delete(f.configs, subject)
+	}
+
+	return versions, nil
+}
+
+// IsRegistered tells if the schema is registered for the subject.
+func (f *FakeRegistry) IsRegistered(ctx context.Context, subject string, schema string) (bool, *Schema, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	active := f.activeVersions(subject)
+	if len(active) == 0 {
+		return false, nil, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	for _, v := range active {
+		if f.schemas[v.id] == schema {
+			return true, f.toSchema(subject, v), nil
+		}
+	}
+
+	return false, nil, nil
+}
+
+// RegisterNewSchema registers the schema for the subject and returns its id.
+//
+// The id of an already registered schema is returned as is. The schema is
+// rejected if it is incompatible with the latest version of the subject.
+func (f *FakeRegistry) RegisterNewSchema(ctx context.Context, subject string, avroSchema string) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	active := f.activeVersions(subject)
+	for _, v := range active {
+		if f.schemas[v.id] == avroSchema {
+			return v.id, nil
+		}
+	}
+
+	if len(active) > 0 && !f.isCompatible(avroSchema, f.schemas[active[len(active)-1].id]) {
+		return -1, fakeError(IncompatibleSchema, "Schema being registered is incompatible with an earlier schema")
+	}
+
+	id, ok := f.ids[avroSchema]
+	if !ok {
+		id = len(f.ids) + 1
+		f.ids[avroSchema] = id
+		f.schemas[id] = avroSchema
+	}
+
+	version := 1
+	if all := f.subjects[subject]; len(all) > 0 {
+		version = all[len(all)-1].version + 1
+	}
+
+	f.subjects[subject] = append(f.subjects[subject], &fakeVersion{version: version, id: id})
+
+	return id, nil
+}
+
+// GetSchemaBySubjectAndVersion returns the schema for a particular subject and
+// version. The options are ignored.
+func (f *FakeRegistry) GetSchemaBySubjectAndVersion(ctx context.Context, subject string, version int, opts ...QueryOption) (*Schema, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	v, err := f.findVersion(subject, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.toSchema(subject, v), nil
+}
+
+// GetLatestSchema returns the latest version of a schema. The options are
+// ignored.
+func (f *FakeRegistry) GetLatestSchema(ctx context.Context, subject string, opts ...QueryOption) (*Schema, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	active := f.activeVersions(subject)
+	if len(active) == 0 {
+		return nil, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	return f.toSchema(subject, active[len(active)-1]), nil
+}
+
+// GetConfig returns the configuration of the subject, or the global one if the
+// subject is empty. The `WithDefaultToGlobal` option is supported.
+func (f *FakeRegistry) GetConfig(ctx context.Context, subject string, opts ...QueryOption) (*Config, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	query := url.Values{}
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	if subject == "" {
+		config := f.global
+		return &config, nil
+	}
+
+	config, ok := f.configs[subject]
+	if !ok && query.Get("defaultToGlobal") == "true" {
+		config := f.global
+		return &config, nil
+	}
+
+	if !ok {
+		return nil, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	return &config, nil
+}
+
+// SetGlobalConfig sets the global configuration.
+func (f *FakeRegistry) SetGlobalConfig(ctx context.Context, config Config) (*Config, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.global = config
+
+	return &config, nil
+}
+
+// DeleteSchemaVersion deletes a specific version of the subject and returns
+// it. The options are ignored.
+func (f *FakeRegistry) DeleteSchemaVersion(ctx context.Context, subject string, version int, permanent bool, opts ...QueryOption) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	v, err := f.findVersion(subject, version)
+	if err != nil {
+		return -1, err
+	}
+
+	f.deleteVersion(subject, v, permanent)
+
+	return v.version, nil
+}
+
+// DeleteLatestSchemaVersion deletes the latest version of the subject and
+// returns it. The options are ignored.
+func (f *FakeRegistry) DeleteLatestSchemaVersion(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	active := f.activeVersions(subject)
+	if len(active) == 0 {
+		return -1, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	v := active[len(active)-1]
+	f.deleteVersion(subject, v, permanent)
+
+	return v.version, nil
+}
+
+// SchemaCompatibleWith tells if the schema is compatible with a particular
+// version of the subject, look `CompatibilityChecker`. The version can be
+// LatestVersion.
+func (f *FakeRegistry) SchemaCompatibleWith(ctx context.Context, schema string, subject string, version int) (bool, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if version == LatestVersion {
+		active := f.activeVersions(subject)
+		if len(active) == 0 {
+			return false, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+		}
+		version = active[len(active)-1].version
+	}
+
+	v, err := f.findVersion(subject, version)
+	if err != nil {
+		return false, err
+	}
+
+	return f.isCompatible(schema, f.schemas[v.id]), nil
+}
+
+func (f *FakeRegistry) activeVersions(subject string) []*fakeVersion {
+	var active []*fakeVersion
+
+	for _, v := range f.subjects[subject] {
+		if !v.deleted {
+			active = append(active, v)
+		}
+	}
+
+	return active
+}
+
+func (f *FakeRegistry) findVersion(subject string, version int) (*fakeVersion, error) {
+	active := f.activeVersions(subject)
+	if len(active) == 0 {
+		return nil, fakeError(SubjectNotFound, "Subject '%s' not found.", subject)
+	}
+
+	for _, v := range active {
+		if v.version == version {
+			return v, nil
+		}
+	}
+
+	return nil, fakeError(VersionNotFound, "Version %d not found.", version)
+}
+
+func (f *FakeRegistry) deleteVersion(subject string, deleted *fakeVersion, permanent bool) {
+	deleted.deleted = true
+
+	if !permanent {
+		return
+	}
+
+	var kept []*fakeVersion
+	for _, v := range f.subjects[subject] {
+		if v != deleted {
+			kept = append(kept, v)
+		}
+	}
+
+	f.subjects[subject] = kept
+}
+
+func (f *FakeRegistry) isCompatible(schema string, existing string) bool {
+	if f.CompatibilityChecker == nil {
+		return true
+	}
+
+	return f.CompatibilityChecker(schema, existing)
+}
+
+func (f *FakeRegistry) toSchema(subject string, v *fakeVersion) *Schema {
+	return &Schema{
+		Schema:  f.schemas[v.id],
+		Subject: subject,
+		Version: v.version,
+		ID:      v.id,
+	}
+}
+
+func fakeError(code int, format string, args ...interface{}) error {
+	return ResourceError{
+		ErrorCode: code,
+		Message:   fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,172 @@
+package schemaregistry
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FakeRegistry_register_then_get(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+
+	id, err := registry.RegisterNewSchema(ctx, "test", `{"type": "string"}`)
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	schema, err := registry.GetSchemaByID(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type": "string"}`, schema)
+
+	latest, err := registry.GetLatestSchema(ctx, "test")
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Schema{
+		Schema:  `{"type": "string"}`,
+		Subject: "test",
+		Version: 1,
+		ID:      1,
+	}, latest)
+
+	exists, registered, err := registry.IsRegistered(ctx, "test", `{"type": "string"}`)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.EqualValues(t, latest, registered)
+}
+
+func Test_FakeRegistry_versions_increment(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+
+	firstID, err := registry.RegisterNewSchema(ctx, "test", `{"type": "string"}`)
+	require.NoError(t, err)
+
+	secondID, err := registry.RegisterNewSchema(ctx, "test", `{"type": "int"}`)
+	require.NoError(t, err)
+
+	// Registering again an existing schema returns its id without a new version.
+	againID, err := registry.RegisterNewSchema(ctx, "test", `{"type": "string"}`)
+	require.NoError(t, err)
+
+	// The ids are shared between the subjects.
+	otherID, err := registry.RegisterNewSchema(ctx, "other", `{"type": "int"}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, firstID)
+	assert.Equal(t, 2, secondID)
+	assert.Equal(t, firstID, againID)
+	assert.Equal(t, secondID, otherID)
+
+	versions, err := registry.Versions(ctx, "test")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2}, versions)
+
+	schema, err := registry.GetSchemaBySubjectAndVersion(ctx, "test", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type": "int"}`, schema.Schema)
+
+	subjects, err := registry.Subjects(ctx)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"other", "test"}, subjects)
+}
+
+func Test_FakeRegistry_delete(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+
+	_, err := registry.RegisterNewSchema(ctx, "test", `{"type": "string"}`)
+	require.NoError(t, err)
+	_, err = registry.RegisterNewSchema(ctx, "test", `{"type": "int"}`)
+	require.NoError(t, err)
+
+	version, err := registry.DeleteLatestSchemaVersion(ctx, "test", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	_, err = registry.GetSchemaBySubjectAndVersion(ctx, "test", 2)
+	assert.True(t, IsVersionNotFound(err))
+
+	// The deleted version numbers are not reused.
+	_, err = registry.RegisterNewSchema(ctx, "test", `{"type": "long"}`)
+	require.NoError(t, err)
+
+	versions, err := registry.DeleteSubject(ctx, "test", false)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 3}, versions)
+
+	_, err = registry.Versions(ctx, "test")
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_FakeRegistry_with_unknown_resources(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+
+	_, err := registry.GetSchemaByID(ctx, 42)
+	assert.True(t, IsSchemaNotFound(err))
+
+	_, err = registry.GetLatestSchema(ctx, "test")
+	assert.True(t, IsSubjectNotFound(err))
+
+	_, _, err = registry.IsRegistered(ctx, "test", `{"type": "string"}`)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_FakeRegistry_compatibility(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+	registry.CompatibilityChecker = func(schema string, existing string) bool {
+		return schema != `{"type": "int"}`
+	}
+
+	_, err := registry.RegisterNewSchema(ctx, "test", `{"type": "string"}`)
+	require.NoError(t, err)
+
+	isCompatible, err := registry.SchemaCompatibleWith(ctx, `{"type": "int"}`, "test", 1)
+	assert.NoError(t, err)
+	assert.False(t, isCompatible)
+
+	isCompatible, err = registry.SchemaCompatibleWith(ctx, `{"type": "int"}`, "test", LatestVersion)
+	assert.NoError(t, err)
+	assert.False(t, isCompatible)
+
+	id, err := registry.RegisterNewSchema(ctx, "test", `{"type": "int"}`)
+	assert.Equal(t, -1, id)
+	assert.True(t, IsIncompatibleSchema(err))
+}
+
+func Test_FakeRegistry_config(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+
+	config, err := registry.SetGlobalConfig(ctx, Config{Compatibility: "FULL"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{Compatibility: "FULL"}, config)
+
+	config, err = registry.GetConfig(ctx, "")
+	assert.NoError(t, err)
+	assert.EqualValues(t, &Config{Compatibility: "FULL"}, config)
+}
+
+func Test_FakeRegistry_concurrent_registrations(t *testing.T) {
+	ctx := context.Background()
+	registry := NewFakeRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := registry.RegisterNewSchema(ctx, "test", `{"type": "string"}`)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	versions, err := registry.Versions(ctx, "test")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1}, versions)
+}
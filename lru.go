@@ -0,0 +1,74 @@
+package schemaregistry
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+// lruCache holds the values of the most recently used keys, the least recently
+// used ones are evicted past maxEntries. It is safe for concurrent use.
+type lruCache struct {
+	maxEntries int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	// lru orders the entries from the most recently used to the least.
+	lru *list.List
+}
+
+func newLRUCache(maxEntries int) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+func (lc *lruCache) get(key string) (interface{}, bool) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	elem, ok := lc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	lc.lru.MoveToFront(elem)
+	return elem.Value.(lruEntry).value, true
+}
+
+// store keeps the value, evicting the least recently used entry if the cache
+// is full.
+func (lc *lruCache) store(key string, value interface{}) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	entry := lruEntry{key: key, value: value}
+
+	if elem, ok := lc.entries[key]; ok {
+		elem.Value = entry
+		lc.lru.MoveToFront(elem)
+		return
+	}
+
+	lc.entries[key] = lc.lru.PushFront(entry)
+
+	if lc.lru.Len() > lc.maxEntries {
+		oldest := lc.lru.Back()
+		lc.lru.Remove(oldest)
+		delete(lc.entries, oldest.Value.(lruEntry).key)
+	}
+}
+
+// len returns the number of entries kept.
+func (lc *lruCache) len() int {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	return lc.lru.Len()
+}
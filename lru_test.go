@@ -0,0 +1,42 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lruCache_evicts_the_least_recently_used_entry(t *testing.T) {
+	cache := newLRUCache(2)
+	cache.store("a", 1)
+	cache.store("b", 2)
+
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	cache.store("c", 3)
+
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+
+	value, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	value, ok = cache.get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+}
+
+func Test_lruCache_replaces_an_entry(t *testing.T) {
+	cache := newLRUCache(2)
+	cache.store("a", 1)
+	cache.store("a", 2)
+	cache.store("b", 3)
+
+	value, ok := cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 2, cache.len())
+}
@@ -0,0 +1,45 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// NormalizeSchema returns the canonical form of a JSON schema (Avro or JSON
+// Schema): the object keys sorted and no insignificant whitespace. The
+// equivalent schemas written differently have the same canonical form, so it
+// can be used to compare them or as a stable cache key, without the server
+// side normalization.
+//
+// The numbers are kept as written. An error is returned if the schema is not a
+// valid JSON.
+func NormalizeSchema(schema string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(schema)))
+	decoder.UseNumber()
+
+	var document interface{}
+	err := decoder.Decode(&document)
+	if err != nil {
+		return "", fmt.Errorf("failed to normalize the schema: %s", err)
+	}
+
+	// The decoder stops after the first value, anything else than the end of
+	// the input (e.g. a trailing "]") is rejected.
+	_, err = decoder.Token()
+	if err != io.EOF {
+		return "", fmt.Errorf("failed to normalize the schema: unexpected data after the schema")
+	}
+
+	// The maps keys are sorted by the encoder.
+	var normalized bytes.Buffer
+	encoder := json.NewEncoder(&normalized)
+	encoder.SetEscapeHTML(false)
+
+	// nolint
+	// Error not possible here, the document have been decoded from JSON.
+	_ = encoder.Encode(document)
+
+	return string(bytes.TrimSuffix(normalized.Bytes(), []byte("\n"))), nil
+}
@@ -0,0 +1,54 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NormalizeSchema_with_equivalent_schemas(t *testing.T) {
+	first, err := NormalizeSchema(`{
+		"type": "record",
+		"name": "user",
+		"fields": [
+			{"name": "id", "type": "long", "default": 0},
+			{"name": "bio", "type": ["null", "string"], "doc": "<b>about</b>"}
+		]
+	}`)
+	require.NoError(t, err)
+
+	second, err := NormalizeSchema(`{"fields":[{"type":"long","default":0,"name":"id"},{"doc":"<b>about</b>","name":"bio","type":["null","string"]}],"name":"user","type":"record"}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, `{"fields":[{"default":0,"name":"id","type":"long"},{"doc":"<b>about</b>","name":"bio","type":["null","string"]}],"name":"user","type":"record"}`, first)
+}
+
+func Test_NormalizeSchema_keeps_the_numbers(t *testing.T) {
+	normalized, err := NormalizeSchema(`{"type": "fixed", "name": "hash", "size": 12345678901234567890}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"hash","size":12345678901234567890,"type":"fixed"}`, normalized)
+}
+
+func Test_NormalizeSchema_with_a_primitive_schema(t *testing.T) {
+	normalized, err := NormalizeSchema(` "string" `)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"string"`, normalized)
+}
+
+func Test_NormalizeSchema_with_an_invalid_schema(t *testing.T) {
+	_, err := NormalizeSchema(`{"type": "record",`)
+	assert.EqualError(t, err, "failed to normalize the schema: unexpected EOF")
+
+	_, err = NormalizeSchema(`{"type": "string"} {}`)
+	assert.EqualError(t, err, "failed to normalize the schema: unexpected data after the schema")
+
+	_, err = NormalizeSchema(`{"a":1}]`)
+	assert.EqualError(t, err, "failed to normalize the schema: unexpected data after the schema")
+
+	_, err = NormalizeSchema(`{"a":1}}`)
+	assert.EqualError(t, err, "failed to normalize the schema: unexpected data after the schema")
+}
@@ -0,0 +1,27 @@
+package schemaregistry
+
+import "context"
+
+type operationKey struct{}
+
+// OperationFromContext returns the name of the client method sending a
+// request, e.g. "RegisterNewSchema", from the request context. It allows the
+// RoundTrippers, the credentials providers and the loggers to label the
+// requests without parsing their URLs.
+//
+// The name is the one of the method called by the application, for the
+// methods sending several requests too.
+func OperationFromContext(ctx context.Context) (string, bool) {
+	operation, ok := ctx.Value(operationKey{}).(string)
+	return operation, ok
+}
+
+// withOperation tags the context with the operation name, unless it is
+// already tagged by an outer method.
+func withOperation(ctx context.Context, operation string) context.Context {
+	if _, ok := OperationFromContext(ctx); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, operationKey{}, operation)
+}
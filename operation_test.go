@@ -0,0 +1,81 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OperationFromContext_with_the_client_methods(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1, "schema": "\"string\"", "compatibilityLevel": "FULL"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	var operations []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		operation, ok := OperationFromContext(req.Context())
+		assert.True(t, ok, req.URL.String())
+		operations = append(operations, operation)
+
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	client, err := NewClient(ts.URL, UsingClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = client.RegisterNewSchema(ctx, "test", `"string"`)
+	require.NoError(t, err)
+	_, err = client.GetSchemaByID(ctx, 1)
+	require.NoError(t, err)
+	_, err = client.GetConfig(ctx, "test")
+	require.NoError(t, err)
+	_, err = client.IsLatest(ctx, "test", `"string"`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"RegisterNewSchema", "GetSchemaByID", "GetConfig", "IsLatest"}, operations)
+
+	_, ok := OperationFromContext(ctx)
+	assert.False(t, ok)
+}
+
+func Test_OperationFromContext_with_several_requests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/subjects/test/versions" {
+			w.WriteHeader(http.StatusConflict)
+			_, err := w.Write([]byte(`{"error_code": 409, "message": "incompatible"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": false, "messages": ["field removed"]}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	var operations []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		operation, ok := OperationFromContext(req.Context())
+		assert.True(t, ok, req.URL.String())
+		operations = append(operations, operation)
+
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	client, err := NewClient(ts.URL, UsingClient(&http.Client{Transport: transport}), UsingVerboseIncompatibility())
+	require.NoError(t, err)
+
+	_, err = client.RegisterNewSchema(context.Background(), "test", `"string"`)
+	require.True(t, IsIncompatibleSchema(err))
+
+	assert.Equal(t, []string{"RegisterNewSchema", "RegisterNewSchema"}, operations)
+}
@@ -0,0 +1,65 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxReferenceDepth bounds the depth of the references followed by
+// ResolveReferences.
+const maxReferenceDepth = 32
+
+// ResolveReferences fetches the schemas referenced by the schema, and the
+// schemas they reference in turn, and returns them by reference name. With the
+// schema itself, they form a self-contained set, for example to generate code.
+//
+// An error is returned on a reference cycle, or if the references are nested
+// deeper than 32 levels.
+func (c *Client) ResolveReferences(ctx context.Context, s *Schema) (map[string]string, error) {
+	ctx = withOperation(ctx, "ResolveReferences")
+
+	resolved := map[string]string{}
+
+	err := c.resolveReferences(ctx, s.References, resolved, map[SubjectVersion]bool{}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// resolveReferences resolves the references into resolved. The path holds the
+// references being resolved, from the root, to detect the cycles.
+func (c *Client) resolveReferences(ctx context.Context, refs []SchemaReference, resolved map[string]string, path map[SubjectVersion]bool, depth int) error {
+	if len(refs) > 0 && depth > maxReferenceDepth {
+		return fmt.Errorf("the references are nested deeper than %d levels", maxReferenceDepth)
+	}
+
+	for _, ref := range refs {
+		item := SubjectVersion{Subject: ref.Subject, Version: ref.Version}
+		if path[item] {
+			return fmt.Errorf("reference cycle detected on %s/%d", ref.Subject, ref.Version)
+		}
+
+		if _, ok := resolved[ref.Name]; ok {
+			continue
+		}
+
+		schema, err := c.GetSchemaBySubjectAndVersion(ctx, ref.Subject, ref.Version)
+		if err != nil {
+			return err
+		}
+
+		path[item] = true
+		err = c.resolveReferences(ctx, schema.References, resolved, path, depth+1)
+		delete(path, item)
+
+		if err != nil {
+			return err
+		}
+
+		resolved[ref.Name] = schema.Schema
+	}
+
+	return nil
+}
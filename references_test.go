@@ -0,0 +1,195 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResolveReferences_with_a_two_level_chain(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/subjects/address/versions/2":
+			body = `{
+				"subject": "address", "version": 2, "id": 2, "schemaType": "PROTOBUF",
+				"schema": "import \"country.proto\"; message Address {}",
+				"references": [{"name": "country.proto", "subject": "country", "version": 1}]
+			}`
+		case "/subjects/country/versions/1":
+			body = `{
+				"subject": "country", "version": 1, "id": 1, "schemaType": "PROTOBUF",
+				"schema": "message Country {}"
+			}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	resolved, err := client.ResolveReferences(context.Background(), &Schema{
+		Schema:     `import "address.proto"; message User {}`,
+		References: []SchemaReference{{Name: "address.proto", Subject: "address", Version: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"address.proto": `import "country.proto"; message Address {}`,
+		"country.proto": "message Country {}",
+	}, resolved)
+}
+
+func Test_ResolveReferences_without_references(t *testing.T) {
+	client, err := NewClient("http://localhost")
+	require.NoError(t, err)
+
+	resolved, err := client.ResolveReferences(context.Background(), &Schema{Schema: `"string"`})
+
+	assert.NoError(t, err)
+	assert.Empty(t, resolved)
+}
+
+func Test_ResolveReferences_with_a_cycle(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/subjects/a/versions/1":
+			body = `{
+				"subject": "a", "version": 1, "id": 1, "schema": "a",
+				"references": [{"name": "b", "subject": "b", "version": 1}]
+			}`
+		case "/subjects/b/versions/1":
+			body = `{
+				"subject": "b", "version": 1, "id": 2, "schema": "b",
+				"references": [{"name": "a", "subject": "a", "version": 1}]
+			}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	resolved, err := client.ResolveReferences(context.Background(), &Schema{
+		References: []SchemaReference{{Name: "a", Subject: "a", Version: 1}},
+	})
+
+	assert.Nil(t, resolved)
+	assert.EqualError(t, err, "reference cycle detected on a/1")
+}
+
+func Test_ResolveReferences_with_a_chain_too_deep(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var i int
+		_, err := fmt.Sscanf(r.URL.Path, "/subjects/s%d/versions/1", &i)
+		require.NoError(t, err)
+		require.True(t, i <= maxReferenceDepth+1, "unexpected request %s", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write([]byte(fmt.Sprintf(`{
+			"subject": "s%d", "version": 1, "id": %d, "schema": "s%d",
+			"references": [{"name": "s%d", "subject": "s%d", "version": 1}]
+		}`, i, i, i, i+1, i+1)))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	resolved, err := client.ResolveReferences(context.Background(), &Schema{
+		References: []SchemaReference{{Name: "s1", Subject: "s1", Version: 1}},
+	})
+
+	assert.Nil(t, resolved)
+	assert.EqualError(t, err, "the references are nested deeper than 32 levels")
+}
+
+func Test_ResolveReferences_with_a_missing_reference(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40402, "message": "version not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	resolved, err := client.ResolveReferences(context.Background(), &Schema{
+		References: []SchemaReference{{Name: "a", Subject: "a", Version: 1}},
+	})
+
+	assert.Nil(t, resolved)
+	assert.True(t, IsVersionNotFound(err))
+}
+
+func Test_SubjectsReferencing_success(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/subjects/common/versions/1/referencedby", "/subjects/common/versions/latest/referencedby":
+			body = `[10, 20]`
+		case "/schemas/ids/10/versions":
+			body = `[{"subject": "orders-value", "version": 3}, {"subject": "orders-value", "version": 2}]`
+		case "/schemas/ids/20/versions":
+			body = `[{"subject": "customers-value", "version": 1}]`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	for _, version := range []int{1, LatestVersion} {
+		referencing, err := client.SubjectsReferencing(context.Background(), "common", version)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []SubjectVersion{
+			{Subject: "customers-value", Version: 1},
+			{Subject: "orders-value", Version: 2},
+			{Subject: "orders-value", Version: 3},
+		}, referencing, version)
+	}
+}
+
+func Test_SubjectsReferencing_without_references(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	referencing, err := client.SubjectsReferencing(context.Background(), "common", 1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, referencing)
+}
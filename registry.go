@@ -0,0 +1,29 @@
+package schemaregistry
+
+import "context"
+
+// Registry describes the operations available on a schema registry.
+//
+// It is implemented by Client, ClientMock and FakeRegistry so the code
+// depending on a registry can be tested without a server.
+type Registry interface {
+	GetSchemaByID(ctx context.Context, subjectID int) (string, error)
+	Subjects(ctx context.Context) (subjects []string, err error)
+	Versions(ctx context.Context, subject string) (versions []int, err error)
+	DeleteSubject(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (versions []int, err error)
+	IsRegistered(ctx context.Context, subject string, schema string) (bool, *Schema, error)
+	RegisterNewSchema(ctx context.Context, subject string, avroSchema string) (int, error)
+	GetSchemaBySubjectAndVersion(ctx context.Context, subject string, version int, opts ...QueryOption) (*Schema, error)
+	GetLatestSchema(ctx context.Context, subject string, opts ...QueryOption) (*Schema, error)
+	GetConfig(ctx context.Context, subject string, opts ...QueryOption) (*Config, error)
+	SetGlobalConfig(ctx context.Context, config Config) (*Config, error)
+	DeleteSchemaVersion(ctx context.Context, subject string, version int, permanent bool, opts ...QueryOption) (int, error)
+	DeleteLatestSchemaVersion(ctx context.Context, subject string, permanent bool, opts ...QueryOption) (int, error)
+	SchemaCompatibleWith(ctx context.Context, schema string, subject string, version int) (bool, error)
+}
+
+var (
+	_ Registry = (*Client)(nil)
+	_ Registry = (*ClientMock)(nil)
+	_ Registry = (*FakeRegistry)(nil)
+)
@@ -0,0 +1,133 @@
+package schemaregistry
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// BackoffFunc returns the time to wait after the given number of failed
+// attempts, starting at 1, before retrying a request.
+type BackoffFunc func(attempt int) time.Duration
+
+// Retry describes how the failed requests are retried. A request is retried if
+// no response have been received or if the server answered with a 5xx or a
+// "429 Too Many Requests" status.
+//
+// Only the GET and DELETE requests are retried by default as they are
+// idempotent.
+type Retry struct {
+	// MaxRetries is the maximum number of retries for a request, none if
+	// zero.
+	MaxRetries int
+	// Delay is the time waited between two attempts, when Backoff is nil.
+	Delay time.Duration
+	// Backoff computes the time waited between two attempts, look
+	// `ExponentialBackoff`, `FullJitterBackoff` and `EqualJitterBackoff`
+	// for the provided strategies. Without Delay nor Backoff, the default
+	// is an EqualJitterBackoff from 100ms up to 5s.
+	Backoff BackoffFunc
+	// RetryPOST enables the retries for the POST requests. Most of them are
+	// idempotent (e.g. registering twice the same schema returns the same
+	// ID) but a registration could be made twice on a server not
+	// deduplicating the schemas, or behind a proxy replaying the requests.
+	RetryPOST bool
+}
+
+// UsingRetry enables the retries of the failed requests, look `Retry` for
+// more. The retries wait an EqualJitterBackoff from 100ms up to 5s when
+// neither Delay nor Backoff is set, so that a failing server isn't hammered.
+func UsingRetry(retry Retry) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// shouldRetry tells if a request failed after the given number of attempts
+// must be retried.
+func (r Retry) shouldRetry(ctx context.Context, method string, statusCode int, attempt int) bool {
+	if attempt > r.MaxRetries || ctx.Err() != nil {
+		return false
+	}
+
+	switch method {
+	case "GET", "DELETE":
+	case "POST":
+		if !r.RetryPOST {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return statusCode == 0 || statusCode >= 500 || statusCode == http.StatusTooManyRequests
+}
+
+// Bounds of the backoff used when the retries have neither Delay nor Backoff.
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// delay returns the time to wait after the given number of failed attempts.
+func (r Retry) delay(attempt int) time.Duration {
+	if r.Backoff == nil {
+		if r.Delay == 0 {
+			return EqualJitterBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay)(attempt)
+		}
+
+		return r.Delay
+	}
+
+	return r.Backoff(attempt)
+}
+
+// ExponentialBackoff returns a BackoffFunc doubling the delay after each
+// attempt, starting at base and capped at max, without jitter.
+func ExponentialBackoff(base time.Duration, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return exponentialDelay(base, max, attempt)
+	}
+}
+
+// FullJitterBackoff returns a BackoffFunc waiting a random time between zero
+// and the exponential delay (see `ExponentialBackoff`). It spreads the most
+// the retries of many clients failing at the same time.
+func FullJitterBackoff(base time.Duration, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return jitter(exponentialDelay(base, max, attempt))
+	}
+}
+
+// EqualJitterBackoff returns a BackoffFunc waiting at least half of the
+// exponential delay (see `ExponentialBackoff`), plus a random time up to the
+// other half.
+func EqualJitterBackoff(base time.Duration, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := exponentialDelay(base, max, attempt)
+		return delay/2 + jitter(delay-delay/2)
+	}
+}
+
+func exponentialDelay(base time.Duration, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
+
+// jitter returns a random duration between zero and d included.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
@@ -0,0 +1,318 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Retry_GET_requests(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 2}))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2}, versions)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func Test_Retry_with_too_many_failures(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 2}))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.Nil(t, versions)
+	assert.True(t, HasErrorCode(err, BackendStoreError))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func Test_Retry_disabled_by_default(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	_, err = client.Versions(context.Background(), "test")
+
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func Test_Retry_POST_requests_disabled(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 2}))
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Equal(t, -1, id)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func Test_Retry_POST_requests_enabled(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"id": 1}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 2, RetryPOST: true}))
+	require.NoError(t, err)
+
+	id, err := client.RegisterNewSchema(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func Test_Retry_with_a_client_error(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 2}))
+	require.NoError(t, err)
+
+	_, err = client.Versions(context.Background(), "test")
+
+	assert.True(t, IsSubjectNotFound(err))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func Test_Retry_with_a_custom_backoff(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	var attempts []int
+	client, err := NewClient(ts.URL, UsingRetry(Retry{
+		MaxRetries: 3,
+		Backoff: func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return time.Millisecond
+		},
+	}))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2}, versions)
+	assert.EqualValues(t, 4, atomic.LoadInt32(&calls))
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func Test_ExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	assert.Equal(t, 100*time.Millisecond, backoff(1))
+	assert.Equal(t, 200*time.Millisecond, backoff(2))
+	assert.Equal(t, 400*time.Millisecond, backoff(3))
+	assert.Equal(t, 800*time.Millisecond, backoff(4))
+	assert.Equal(t, time.Second, backoff(5))
+	assert.Equal(t, time.Second, backoff(100))
+}
+
+func Test_FullJitterBackoff(t *testing.T) {
+	backoff := FullJitterBackoff(100*time.Millisecond, time.Second)
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		max := ExponentialBackoff(100*time.Millisecond, time.Second)(attempt)
+		delay := backoff(attempt)
+
+		assert.True(t, delay >= 0 && delay <= max, "attempt %d: %s", attempt, delay)
+	}
+}
+
+func Test_EqualJitterBackoff(t *testing.T) {
+	backoff := EqualJitterBackoff(100*time.Millisecond, time.Second)
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		max := ExponentialBackoff(100*time.Millisecond, time.Second)(attempt)
+		delay := backoff(attempt)
+
+		assert.True(t, delay >= max/2 && delay <= max, "attempt %d: %s", attempt, delay)
+	}
+}
+
+func Test_Retry_waits_the_delay_between_attempts(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 2, Delay: time.Hour}), usingClock(clk))
+	require.NoError(t, err)
+
+	versions, err := client.Versions(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int{1, 2}, versions)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	assert.Equal(t, []time.Duration{time.Hour, time.Hour}, clk.Waits())
+}
+
+func Test_Retry_waits_a_bounded_backoff_by_default(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 8 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, UsingRetry(Retry{MaxRetries: 8}), usingClock(clk))
+	require.NoError(t, err)
+
+	_, err = client.Versions(context.Background(), "test")
+
+	assert.NoError(t, err)
+
+	waits := clk.Waits()
+	require.Len(t, waits, 8)
+	for i, wait := range waits {
+		max := ExponentialBackoff(100*time.Millisecond, 5*time.Second)(i + 1)
+		assert.True(t, wait >= max/2 && wait <= max, "attempt %d: %s", i+1, wait)
+	}
+}
+
+func Test_Retry_waits_the_backoff_between_attempts(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 4 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1, 2]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, UsingRetry(Retry{
+		MaxRetries: 4,
+		Backoff:    ExponentialBackoff(time.Second, 5*time.Second),
+	}), usingClock(clk))
+	require.NoError(t, err)
+
+	_, err = client.Versions(context.Background(), "test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}, clk.Waits())
+}
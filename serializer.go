@@ -0,0 +1,238 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Serializer resolves the schema IDs required by the producers to frame their
+// messages.
+//
+// The IDs are cached per subject and schema so after the warmup (see `Warmup`)
+// no network call is made. A Serializer is safe for concurrent use.
+type Serializer struct {
+	client Registry
+
+	lock  sync.RWMutex
+	cache map[string]map[string]int
+}
+
+// NewSerializer instantiate a new Serializer using the given registry client.
+func NewSerializer(client Registry) *Serializer {
+	return &Serializer{
+		client: client,
+		cache:  map[string]map[string]int{},
+	}
+}
+
+// PrefixFor returns the wire-format header to prepend to the messages encoded
+// with the given schema.
+//
+// The schema is registered for the subject if it is not already.
+func (s *Serializer) PrefixFor(ctx context.Context, subject string, schema string) ([]byte, error) {
+	header, _, err := s.WireHeader(ctx, subject, schema)
+	return header, err
+}
+
+// WireHeader works like `PrefixFor` and returns the schema ID too, in a single
+// call per message.
+//
+// An error is returned if the ID doesn't fit in the 4 bytes of the header.
+func (s *Serializer) WireHeader(ctx context.Context, subject string, schema string) ([]byte, int, error) {
+	id, err := s.schemaID(ctx, subject, schema)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return EncodePayload(id, nil), id, nil
+}
+
+// Invalidate removes all the cached IDs for the subject. It must be called once
+// a subject is deleted in order to register again its schemas.
+func (s *Serializer) Invalidate(subject string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.cache, subject)
+}
+
+func (s *Serializer) schemaID(ctx context.Context, subject string, schema string) (int, error) {
+	s.lock.RLock()
+	id, ok := s.cache[subject][schema]
+	s.lock.RUnlock()
+
+	if ok {
+		return id, nil
+	}
+
+	// The schema may be cached in another form, e.g. the canonical string of
+	// the registry stored by the warmup.
+	s.lock.RLock()
+	id, ok = s.cache[subject][schemaCacheKey(schema)]
+	s.lock.RUnlock()
+
+	if ok {
+		return id, s.store(subject, schema, id)
+	}
+
+	isRegistered, registered, err := s.client.IsRegistered(ctx, subject, schema)
+	if err != nil && !IsSubjectNotFound(err) {
+		return -1, err
+	}
+
+	if isRegistered {
+		id = registered.ID
+	} else {
+		id, err = s.client.RegisterNewSchema(ctx, subject, schema)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	err = s.store(subject, schema, id)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// store caches the ID of the schema for the subject, once checked. It is
+// cached under the schema as given, for the next lookups to be a single map
+// access, and under its normalized form to match the other forms of the
+// schema.
+func (s *Serializer) store(subject string, schema string, id int) error {
+	if id < 0 || int64(id) > math.MaxUint32 {
+		return fmt.Errorf("invalid schema ID %d: it doesn't fit in the wire-format header", id)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.cache[subject]; !ok {
+		s.cache[subject] = map[string]int{}
+	}
+
+	s.cache[subject][schema] = id
+	s.cache[subject][schemaCacheKey(schema)] = id
+
+	return nil
+}
+
+// schemaCacheKey returns the normalized form of the schema, see
+// `NormalizeSchema`, or the compacted one for the schemas which are not JSON.
+func schemaCacheKey(schema string) string {
+	normalized, err := NormalizeSchema(schema)
+	if err != nil {
+		return compactSchema(schema)
+	}
+
+	return normalized
+}
+
+// WarmupOption function used to modify the behavior of `Serializer.Warmup`.
+type WarmupOption func(*warmupOptions)
+
+type warmupOptions struct {
+	failFast bool
+}
+
+// WithFailFast stops the warmup at the first subject failing, the remaining
+// subjects are skipped.
+func WithFailFast() WarmupOption {
+	return func(opts *warmupOptions) {
+		opts.failFast = true
+	}
+}
+
+// Warmup fetches the latest schema of each subject and caches its ID, so the
+// first messages encoded with these schemas don't wait for the registry. It is
+// meant to be called at startup, for the subjects known in advance. The ID is
+// looked up if the registry omits it.
+//
+// With a client caching the configurations (see `UsingConfigCache`), the
+// configuration of each subject is fetched too in order to fill the cache. It
+// is best-effort, a failure doesn't fail the subject.
+//
+// The subjects are fetched concurrently, look `UsingConcurrency`. By default a failing subject doesn't
+// stop the others: the failures are returned in a MultiError keyed by subject.
+// Look `WithFailFast` to stop at the first one.
+func (s *Serializer) Warmup(ctx context.Context, subjects []string, opts ...WarmupOption) error {
+	var options warmupOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	concurrency := defaultConcurrency
+	if client, ok := s.client.(*Client); ok {
+		concurrency = client.concurrency
+	}
+
+	errs := make([]error, len(subjects))
+
+	err := forEachConcurrently(ctx, concurrency, len(subjects), func(ctx context.Context, i int) error {
+		errs[i] = s.warmup(ctx, subjects[i])
+		if options.failFast && errs[i] != nil {
+			return MultiError{SubjectVersion{Subject: subjects[i]}: errs[i]}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	multiErr := MultiError{}
+	for i, subject := range subjects {
+		if errs[i] != nil {
+			multiErr[SubjectVersion{Subject: subject}] = errs[i]
+		}
+	}
+
+	if len(multiErr) > 0 {
+		return multiErr
+	}
+
+	return nil
+}
+
+func (s *Serializer) warmup(ctx context.Context, subject string) error {
+	latest, err := s.client.GetLatestSchema(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	// Some registries omit the ID on the version endpoint, it is looked up
+	// like in `GetLatestSchemaMetadata`.
+	if latest.ID == 0 {
+		isRegistered, registered, err := s.client.IsRegistered(ctx, subject, latest.Schema)
+		if err != nil {
+			return err
+		}
+
+		if !isRegistered || registered.ID == 0 {
+			return fmt.Errorf("failed to resolve the ID of the latest schema of subject %q", subject)
+		}
+
+		latest.ID = registered.ID
+	}
+
+	err = s.store(subject, latest.Schema, latest.ID)
+	if err != nil {
+		return err
+	}
+
+	client, ok := s.client.(*Client)
+	if !ok || client.configCache == nil {
+		return nil
+	}
+
+	// nolint
+	// The serializer doesn't need the configuration, it is only fetched to
+	// fill the cache of the client.
+	_, _ = client.GetConfig(ctx, subject)
+
+	return nil
+}
@@ -0,0 +1,514 @@
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Serializer_PrefixFor_with_a_registered_schema(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/subjects/test", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 1, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	prefix, err := serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x2a}, prefix)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_Serializer_PrefixFor_with_a_cache_hit(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 1, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	_, err = serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+	require.NoError(t, err)
+
+	prefix, err := serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x2a}, prefix)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_Serializer_PrefixFor_with_an_unregistered_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "/subjects/test":
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+			require.NoError(t, err)
+		case "/subjects/test/versions":
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"id": 7}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	prefix, err := serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x07}, prefix)
+}
+
+func Test_Serializer_PrefixFor_after_an_invalidation(t *testing.T) {
+	registrations := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.String() {
+		case "/subjects/test":
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+			require.NoError(t, err)
+		case "/subjects/test/versions":
+			registrations++
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"id": 7}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	_, err = serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+	require.NoError(t, err)
+
+	serializer.Invalidate("test")
+
+	prefix, err := serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x07}, prefix)
+	assert.Equal(t, 2, registrations)
+}
+
+func Test_Serializer_PrefixFor_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err := w.Write([]byte(`{"error_code": 500, "message": "internal server error"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	prefix, err := serializer.PrefixFor(context.Background(), "test", `{"type": "string"}`)
+
+	assert.Nil(t, prefix)
+	assert.Error(t, err)
+}
+
+func Test_Serializer_WireHeader_with_a_cache_miss_then_a_hit(t *testing.T) {
+	mock := new(ClientMock)
+	mock.On("IsRegistered", "test", `{"type": "string"}`).Return(false, nil, nil).Once()
+	mock.On("RegisterNewSchema", "test", `{"type": "string"}`).Return(258, nil).Once()
+
+	serializer := NewSerializer(mock)
+
+	for i := 0; i < 2; i++ {
+		header, id, err := serializer.WireHeader(context.Background(), "test", `{"type": "string"}`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x01, 0x02}, header)
+		assert.Equal(t, 258, id)
+	}
+
+	mock.AssertExpectations(t)
+}
+
+func Test_Serializer_WireHeader_with_an_id_too_large(t *testing.T) {
+	if strconv.IntSize < 64 {
+		t.Skip("the IDs can't exceed 4 bytes on 32-bit platforms")
+	}
+
+	tooLarge := int64(1) << 32
+
+	mock := new(ClientMock)
+	mock.On("IsRegistered", "test", `{"type": "string"}`).Return(true, &Schema{ID: int(tooLarge)}, nil)
+
+	serializer := NewSerializer(mock)
+
+	header, id, err := serializer.WireHeader(context.Background(), "test", `{"type": "string"}`)
+
+	assert.EqualError(t, err, "invalid schema ID 4294967296: it doesn't fit in the wire-format header")
+	assert.Nil(t, header)
+	assert.Equal(t, -1, id)
+}
+
+func Test_Serializer_Warmup_fills_the_caches(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/subjects/users/versions/latest":
+			status, body = http.StatusOK, `{"subject": "users", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`
+		case "/subjects/accounts/versions/latest":
+			status, body = http.StatusOK, `{"subject": "accounts", "id": 43, "version": 1, "schema": "{\"type\": \"long\"}"}`
+		case "/config/users":
+			status, body = http.StatusOK, `{"compatibilityLevel": "FULL"}`
+		case "/config/accounts":
+			status, body = http.StatusNotFound, `{"error_code": 40408, "message": "Subject 'accounts' does not have subject-level compatibility configured"}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"users", "accounts"})
+	require.NoError(t, err)
+	require.EqualValues(t, 4, atomic.LoadInt32(&calls))
+
+	prefix, err := serializer.PrefixFor(context.Background(), "users", `{"type": "string"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, EncodePayload(42, nil), prefix)
+
+	prefix, err = serializer.PrefixFor(context.Background(), "accounts", `{"type": "long"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, EncodePayload(43, nil), prefix)
+
+	config, err := client.GetConfig(context.Background(), "users")
+	assert.NoError(t, err)
+	assert.Equal(t, Full, config.Compatibility)
+
+	assert.EqualValues(t, 4, atomic.LoadInt32(&calls))
+}
+
+func Test_Serializer_Warmup_with_another_schema_form(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		assert.Equal(t, "/subjects/orders/versions/latest", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "orders", "id": 44, "version": 2, "schema": "{\"type\":\"record\",\"name\":\"order\",\"fields\":[{\"name\":\"id\",\"type\":\"long\"}]}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"orders"})
+	require.NoError(t, err)
+
+	callsAfterWarmup := atomic.LoadInt32(&calls)
+
+	prefix, err := serializer.PrefixFor(context.Background(), "orders", `{
+		"name": "order",
+		"type": "record",
+		"fields": [{"type": "long", "name": "id"}]
+	}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, EncodePayload(44, nil), prefix)
+	assert.Equal(t, callsAfterWarmup, atomic.LoadInt32(&calls))
+}
+
+func Test_Serializer_Warmup_without_config_cache(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var body string
+		switch r.URL.String() {
+		case "/subjects/users/versions/latest":
+			body = `{"subject": "users", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`
+		case "/subjects/accounts/versions/latest":
+			body = `{"subject": "accounts", "id": 43, "version": 1, "schema": "{\"type\": \"long\"}"}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"users", "accounts"})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func Test_Serializer_Warmup_with_a_failing_config(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/subjects/orders/versions/latest":
+			status, body = http.StatusOK, `{"subject": "orders", "id": 44, "version": 2, "schema": "{\"type\": \"long\"}"}`
+		case "/config/orders":
+			status, body = http.StatusInternalServerError, `{"error_code": 50001, "message": "Error in the backend data store"}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConfigCache(time.Hour))
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"orders"})
+
+	assert.NoError(t, err)
+}
+
+func Test_Serializer_Warmup_without_id_in_the_latest_schema(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		switch r.URL.String() {
+		case "/subjects/users/versions/latest":
+			assert.Equal(t, "GET", r.Method)
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"subject": "users", "version": 3, "schema": "{\"type\": \"string\"}"}`))
+			require.NoError(t, err)
+		case "/subjects/users":
+			assert.Equal(t, "POST", r.Method)
+
+			w.WriteHeader(http.StatusOK)
+			_, err := w.Write([]byte(`{"subject": "users", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`))
+			require.NoError(t, err)
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"users"})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	prefix, err := serializer.PrefixFor(context.Background(), "users", `{"type": "string"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, EncodePayload(42, nil), prefix)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func Test_Serializer_Warmup_without_id_in_an_unregistered_latest_schema(t *testing.T) {
+	mock := new(ClientMock)
+	mock.On("GetLatestSchema", "users").Return(&Schema{Schema: `{"type": "string"}`, Subject: "users", Version: 3}, nil)
+	mock.On("IsRegistered", "users", `{"type": "string"}`).Return(false, nil, nil)
+
+	serializer := NewSerializer(mock)
+
+	err := serializer.Warmup(context.Background(), []string{"users"})
+
+	var multiErr MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.EqualError(t, multiErr[SubjectVersion{Subject: "users"}], `failed to resolve the ID of the latest schema of subject "users"`)
+	mock.AssertExpectations(t)
+}
+
+func Test_schemaCacheKey(t *testing.T) {
+	assert.Equal(t, `{"name":"a","type":"string"}`, schemaCacheKey(`{ "type": "string", "name": "a" }`))
+	assert.Equal(t, `syntax = "proto3";`, schemaCacheKey("  syntax = \"proto3\";\n"))
+}
+
+func Test_Serializer_Warmup_with_a_failing_subject(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/subjects/users/versions/latest":
+			status, body = http.StatusOK, `{"subject": "users", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`
+		case "/subjects/accounts/versions/latest":
+			status, body = http.StatusOK, `{"subject": "accounts", "id": 43, "version": 1, "schema": "{\"type\": \"long\"}"}`
+		case "/subjects/missing/versions/latest":
+			status, body = http.StatusNotFound, `{"error_code": 40401, "message": "Subject 'missing' not found."}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"users", "missing", "accounts"})
+
+	var multiErr MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Len(t, multiErr, 1)
+	assert.True(t, IsSubjectNotFound(multiErr[SubjectVersion{Subject: "missing"}]))
+
+	callsAfterWarmup := atomic.LoadInt32(&calls)
+
+	_, err = serializer.PrefixFor(context.Background(), "users", `{"type": "string"}`)
+	assert.NoError(t, err)
+	_, err = serializer.PrefixFor(context.Background(), "accounts", `{"type": "long"}`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, callsAfterWarmup, atomic.LoadInt32(&calls))
+}
+
+func Test_Serializer_Warmup_with_fail_fast(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status int
+		var body string
+		switch r.URL.String() {
+		case "/subjects/users/versions/latest":
+			status, body = http.StatusOK, `{"subject": "users", "id": 42, "version": 3, "schema": "{\"type\": \"string\"}"}`
+		case "/subjects/missing/versions/latest":
+			status, body = http.StatusNotFound, `{"error_code": 40401, "message": "Subject 'missing' not found."}`
+		default:
+			assert.Fail(t, "unexpected request", r.URL.String())
+		}
+
+		w.WriteHeader(status)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"missing", "users"}, WithFailFast())
+
+	var multiErr MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Len(t, multiErr, 1)
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_Serializer_Warmup_uses_the_client_concurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var once sync.Once
+	concurrent := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		// The first requests are held until two of them run concurrently.
+		if current == 2 {
+			once.Do(func() { close(concurrent) })
+		}
+		<-concurrent
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"subject": "test", "id": 42, "version": 1, "schema": "{\"type\": \"string\"}"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, UsingConcurrency(2))
+	require.NoError(t, err)
+
+	serializer := NewSerializer(client)
+
+	err = serializer.Warmup(context.Background(), []string{"a", "b", "c", "d", "e", "f"})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&maxInFlight))
+}
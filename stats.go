@@ -0,0 +1,52 @@
+package schemaregistry
+
+import "sync/atomic"
+
+// Stats holds the counters accumulated by a client since its creation, look
+// `Client.Stats`.
+type Stats struct {
+	// Requests is the number of HTTP requests sent, the retries included.
+	Requests int64
+	// Retries is the number of requests sent again after a failure.
+	Retries int64
+	// CacheHits is the number of responses served by the client caches
+	// (look `UsingConfigCache` and `UsingETagCache`) instead of the server.
+	CacheHits int64
+	// CacheMisses is the number of lookups in the client caches requiring
+	// the response of the server.
+	CacheMisses int64
+	// Errors is the number of calls failed, after the retries.
+	Errors int64
+}
+
+// clientStats holds the counters of a client. They are only updated
+// atomically.
+type clientStats struct {
+	requests    int64
+	retries     int64
+	cacheHits   int64
+	cacheMisses int64
+	errors      int64
+}
+
+// Stats returns the counters accumulated by the client. They are cheap to
+// maintain and give a quick visibility on the client activity without a full
+// metrics integration.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests:    atomic.LoadInt64(&c.stats.requests),
+		Retries:     atomic.LoadInt64(&c.stats.retries),
+		CacheHits:   atomic.LoadInt64(&c.stats.cacheHits),
+		CacheMisses: atomic.LoadInt64(&c.stats.cacheMisses),
+		Errors:      atomic.LoadInt64(&c.stats.errors),
+	}
+}
+
+// countCacheLookup counts a lookup in one of the client caches.
+func (s *clientStats) countCacheLookup(hit bool) {
+	if hit {
+		atomic.AddInt64(&s.cacheHits, 1)
+	} else {
+		atomic.AddInt64(&s.cacheMisses, 1)
+	}
+}
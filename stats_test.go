@@ -0,0 +1,96 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Stats_counters(t *testing.T) {
+	var versionsCalls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/subjects/test/versions":
+			if atomic.AddInt32(&versionsCalls, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, err := w.Write([]byte(`{"error_code": 50001, "message": "store error"}`))
+				require.NoError(t, err)
+				return
+			}
+			body = `[1, 2]`
+		case "/config/test":
+			body = `{"compatibilityLevel": "FULL"}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40403, "message": "Schema not found"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL,
+		UsingRetry(Retry{MaxRetries: 2, Delay: time.Hour}),
+		UsingConfigCache(time.Hour),
+		usingClock(newFakeClock()))
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{}, client.Stats())
+
+	_, err = client.Versions(context.Background(), "test")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.GetConfig(context.Background(), "test")
+		require.NoError(t, err)
+	}
+
+	_, err = client.GetSchemaByID(context.Background(), 42)
+	require.True(t, IsSchemaNotFound(err))
+
+	assert.Equal(t, Stats{
+		Requests:    4,
+		Retries:     1,
+		CacheHits:   1,
+		CacheMisses: 1,
+		Errors:      1,
+	}, client.Stats())
+}
+
+func Test_Stats_with_concurrent_calls(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Versions(context.Background(), "test")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 20, client.Stats().Requests)
+}
@@ -0,0 +1,69 @@
+package schemaregistry
+
+import "strings"
+
+// The subject suffixes of the keys and the values with the TopicNameStrategy.
+const (
+	keySuffix   = "-key"
+	valueSuffix = "-value"
+)
+
+// SubjectNameStrategy computes the subject name under which a schema is
+// registered for a given topic. The isKey flag tells if the schema describes
+// the message key or the message value and recordFullName is the fully
+// qualified name of the record (namespace included).
+//
+// The result can be passed to RegisterNewSchema or IsRegistered.
+//
+// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#subject-name-strategy
+type SubjectNameStrategy func(topic string, isKey bool, recordFullName string) string
+
+// TopicNameStrategy derives the subject name from the topic name with a "-key"
+// or "-value" suffix. This is the default strategy used by the Kafka
+// serializers.
+func TopicNameStrategy(topic string, isKey bool, recordFullName string) string {
+	if isKey {
+		return KeySubject(topic)
+	}
+
+	return ValueSubject(topic)
+}
+
+// KeySubject returns the subject of the topic message keys with the
+// `TopicNameStrategy`.
+func KeySubject(topic string) string {
+	return topic + keySuffix
+}
+
+// ValueSubject returns the subject of the topic message values with the
+// `TopicNameStrategy`.
+func ValueSubject(topic string) string {
+	return topic + valueSuffix
+}
+
+// ParseTopicSubject extracts the topic from a subject named with the
+// `TopicNameStrategy` and tells if it is the subject of the message keys. Only
+// the last suffix is removed, so the topic can contain hyphens or even end with
+// "-key" itself. ok is false if the subject has none of the suffixes.
+func ParseTopicSubject(subject string) (topic string, isKey bool, ok bool) {
+	switch {
+	case strings.HasSuffix(subject, keySuffix) && len(subject) > len(keySuffix):
+		return strings.TrimSuffix(subject, keySuffix), true, true
+	case strings.HasSuffix(subject, valueSuffix) && len(subject) > len(valueSuffix):
+		return strings.TrimSuffix(subject, valueSuffix), false, true
+	default:
+		return "", false, false
+	}
+}
+
+// RecordNameStrategy derives the subject name from the fully qualified record
+// name, allowing several record types in the same topic.
+func RecordNameStrategy(topic string, isKey bool, recordFullName string) string {
+	return recordFullName
+}
+
+// TopicRecordNameStrategy derives the subject name from both the topic and the
+// fully qualified record name.
+func TopicRecordNameStrategy(topic string, isKey bool, recordFullName string) string {
+	return topic + "-" + recordFullName
+}
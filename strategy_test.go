@@ -0,0 +1,66 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TopicNameStrategy(t *testing.T) {
+	var strategy SubjectNameStrategy = TopicNameStrategy
+
+	assert.Equal(t, "some-topic-key", strategy("some-topic", true, "com.example.User"))
+	assert.Equal(t, "some-topic-value", strategy("some-topic", false, "com.example.User"))
+}
+
+func Test_RecordNameStrategy(t *testing.T) {
+	var strategy SubjectNameStrategy = RecordNameStrategy
+
+	assert.Equal(t, "com.example.User", strategy("some-topic", true, "com.example.User"))
+	assert.Equal(t, "com.example.User", strategy("some-topic", false, "com.example.User"))
+}
+
+func Test_TopicRecordNameStrategy(t *testing.T) {
+	var strategy SubjectNameStrategy = TopicRecordNameStrategy
+
+	assert.Equal(t, "some-topic-com.example.User", strategy("some-topic", true, "com.example.User"))
+	assert.Equal(t, "some-topic-com.example.User", strategy("some-topic", false, "com.example.User"))
+}
+
+func Test_KeySubject_and_ValueSubject(t *testing.T) {
+	assert.Equal(t, "orders-key", KeySubject("orders"))
+	assert.Equal(t, "orders-value", ValueSubject("orders"))
+	assert.Equal(t, "my-orders-v2-value", ValueSubject("my-orders-v2"))
+}
+
+func Test_ParseTopicSubject(t *testing.T) {
+	for _, tc := range []struct {
+		subject string
+		topic   string
+		isKey   bool
+		ok      bool
+	}{
+		{subject: "orders-key", topic: "orders", isKey: true, ok: true},
+		{subject: "orders-value", topic: "orders", isKey: false, ok: true},
+		{subject: "my-orders-v2-value", topic: "my-orders-v2", isKey: false, ok: true},
+		{subject: "primary-key-value", topic: "primary-key", isKey: false, ok: true},
+		{subject: "orders-value-key", topic: "orders-value", isKey: true, ok: true},
+		{subject: "orders", ok: false},
+		{subject: "-key", ok: false},
+		{subject: "com.example.Order", ok: false},
+	} {
+		topic, isKey, ok := ParseTopicSubject(tc.subject)
+
+		assert.Equal(t, tc.topic, topic, tc.subject)
+		assert.Equal(t, tc.isKey, isKey, tc.subject)
+		assert.Equal(t, tc.ok, ok, tc.subject)
+	}
+}
+
+func Test_ParseTopicSubject_round_trip(t *testing.T) {
+	topic, isKey, ok := ParseTopicSubject(TopicNameStrategy("my-topic", true, ""))
+
+	assert.True(t, ok)
+	assert.True(t, isKey)
+	assert.Equal(t, "my-topic", topic)
+}
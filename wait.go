@@ -0,0 +1,85 @@
+package schemaregistry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WaitForSubject polls the subject versions every pollInterval until the
+// subject exists. It smooths the startup races with a just provisioned
+// registry.
+//
+// Only the subject not found errors are polled again, the other errors (e.g.
+// an AuthError) are returned at once. Once the context is done, the error
+// returned by the last poll is returned.
+func (c *Client) WaitForSubject(ctx context.Context, subject string, pollInterval time.Duration) error {
+	ctx = withOperation(ctx, "WaitForSubject")
+
+	var lastErr error
+
+	for {
+		_, err := c.Versions(ctx, subject)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() == nil && !IsSubjectNotFound(err) {
+			return err
+		}
+
+		// A poll interrupted by the context doesn't tell anything about the
+		// subject, so the previous error is kept.
+		if ctx.Err() == nil || lastErr == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-c.clock.After(pollInterval):
+		}
+	}
+}
+
+// WaitUntilCompatible polls the compatibility of the schema with the latest
+// version of the subject every pollInterval until it is compatible. It gates
+// the CI jobs on an upstream schema being updated concurrently.
+//
+// Only the incompatible results and the subject or version not found errors
+// are polled again, the other errors (e.g. an invalid schema) are returned at
+// once. Once the context is done, the error returned by the last poll is
+// returned, an `IncompatibleSchemaError` with the verbose messages of the
+// server if the schema was still incompatible.
+func (c *Client) WaitUntilCompatible(ctx context.Context, subject string, schema string, pollInterval time.Duration) error {
+	ctx = withOperation(ctx, "WaitUntilCompatible")
+
+	path := fmt.Sprintf("compatibility/subjects/%s/versions/latest?verbose=true", escapeSubject(subject))
+
+	var lastErr error
+
+	for {
+		compatible, messages, err := c.checkCompatibility(ctx, CompatibilityRequest{Schema: schema}, path)
+		if err == nil && compatible {
+			return nil
+		}
+
+		if err == nil {
+			err = IncompatibleSchemaError{Subject: subject, Messages: messages}
+		} else if ctx.Err() == nil && !IsSubjectNotFound(err) && !IsVersionNotFound(err) {
+			return err
+		}
+
+		// A poll interrupted by the context doesn't tell anything about the
+		// compatibility, so the previous error is kept.
+		if ctx.Err() == nil || lastErr == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-c.clock.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WaitForSubject_with_an_existing_subject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.WaitForSubject(context.Background(), "test", time.Hour)
+
+	assert.NoError(t, err)
+}
+
+func Test_WaitForSubject_with_an_eventual_subject(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.WaitForSubject(context.Background(), "test", time.Millisecond)
+
+	assert.NoError(t, err)
+}
+
+func Test_WaitForSubject_with_a_cancelled_context(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = client.WaitForSubject(ctx, "test", 10*time.Millisecond)
+
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_WaitForSubject_waits_the_poll_interval(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 3 {
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+			require.NoError(t, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`[1]`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, usingClock(clk))
+	require.NoError(t, err)
+
+	err = client.WaitForSubject(context.Background(), "test", time.Hour)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Hour, time.Hour, time.Hour}, clk.Waits())
+}
+
+func Test_WaitForSubject_with_an_auth_error(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.WaitForSubject(context.Background(), "test", time.Millisecond)
+
+	assert.True(t, IsAuthError(err))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func Test_WaitUntilCompatible_with_a_compatible_schema(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions/latest?verbose=true", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": true}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.WaitUntilCompatible(context.Background(), "test", `"string"`, time.Hour)
+
+	assert.NoError(t, err)
+}
+
+func Test_WaitUntilCompatible_with_an_eventually_compatible_schema(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions/latest?verbose=true", r.URL.String())
+
+		body := `{"is_compatible": true}`
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			body = `{"is_compatible": false, "messages": ["reader field id has no default"]}`
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	clk := newFakeClock()
+	client, err := NewClient(ts.URL, usingClock(clk))
+	require.NoError(t, err)
+
+	err = client.WaitUntilCompatible(context.Background(), "test", `"string"`, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Minute, time.Minute}, clk.Waits())
+}
+
+func Test_WaitUntilCompatible_with_a_timeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/compatibility/subjects/test/versions/latest?verbose=true", r.URL.String())
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(`{"is_compatible": false, "messages": ["reader field id has no default"]}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = client.WaitUntilCompatible(ctx, "test", `"string"`, 10*time.Millisecond)
+
+	assert.True(t, IsIncompatibleSchema(err))
+	assert.Equal(t, IncompatibleSchemaError{
+		Subject:  "test",
+		Messages: []string{"reader field id has no default"},
+	}, err)
+}
+
+func Test_WaitUntilCompatible_with_a_remote_error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error_code": 40401, "message": "subject not found"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = client.WaitUntilCompatible(ctx, "test", `"string"`, 10*time.Millisecond)
+
+	assert.True(t, IsSubjectNotFound(err))
+}
+
+func Test_WaitUntilCompatible_with_an_invalid_schema(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err := w.Write([]byte(`{"error_code": 42201, "message": "Invalid schema"}`))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL)
+	require.NoError(t, err)
+
+	err = client.WaitUntilCompatible(context.Background(), "test", `{"type": "unknown"}`, time.Millisecond)
+
+	assert.True(t, IsInvalidSchema(err))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,46 @@
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// These values describe the Confluent wire format used to frame the Kafka
+// messages: a magic byte followed by the schema ID as a 4-byte big-endian
+// integer.
+const (
+	magicByte        = 0x00
+	wireHeaderLength = 5
+)
+
+// EncodePayload prepends the Confluent wire-format header to the payload.
+//
+// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
+func EncodePayload(schemaID int, payload []byte) []byte {
+	msg := make([]byte, wireHeaderLength+len(payload))
+
+	msg[0] = magicByte
+	binary.BigEndian.PutUint32(msg[1:wireHeaderLength], uint32(schemaID))
+	copy(msg[wireHeaderLength:], payload)
+
+	return msg
+}
+
+// DecodePayload strips the Confluent wire-format header from the message and
+// returns the schema ID found in it with the remaining payload.
+//
+// It returns an error if the message is too short to contain the header or if
+// the magic byte is invalid.
+func DecodePayload(msg []byte) (schemaID int, payload []byte, err error) {
+	if len(msg) < wireHeaderLength {
+		return 0, nil, fmt.Errorf("invalid message: expected at least %d bytes, got %d", wireHeaderLength, len(msg))
+	}
+
+	if msg[0] != magicByte {
+		return 0, nil, fmt.Errorf("invalid message: unknown magic byte %#x", msg[0])
+	}
+
+	schemaID = int(binary.BigEndian.Uint32(msg[1:wireHeaderLength]))
+
+	return schemaID, msg[wireHeaderLength:], nil
+}
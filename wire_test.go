@@ -0,0 +1,47 @@
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EncodePayload(t *testing.T) {
+	msg := EncodePayload(42, []byte("some-payload"))
+
+	assert.Equal(t, append([]byte{0x00, 0x00, 0x00, 0x00, 0x2a}, []byte("some-payload")...), msg)
+}
+
+func Test_EncodePayload_DecodePayload_round_trip(t *testing.T) {
+	msg := EncodePayload(123456, []byte("some-payload"))
+
+	schemaID, payload, err := DecodePayload(msg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 123456, schemaID)
+	assert.Equal(t, []byte("some-payload"), payload)
+}
+
+func Test_DecodePayload_with_an_empty_payload(t *testing.T) {
+	schemaID, payload, err := DecodePayload([]byte{0x00, 0x00, 0x00, 0x00, 0x01})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, schemaID)
+	assert.Empty(t, payload)
+}
+
+func Test_DecodePayload_with_an_invalid_magic_byte(t *testing.T) {
+	schemaID, payload, err := DecodePayload([]byte{0x01, 0x00, 0x00, 0x00, 0x01, 0x42})
+
+	assert.Equal(t, 0, schemaID)
+	assert.Nil(t, payload)
+	assert.EqualError(t, err, "invalid message: unknown magic byte 0x1")
+}
+
+func Test_DecodePayload_with_a_truncated_message(t *testing.T) {
+	schemaID, payload, err := DecodePayload([]byte{0x00, 0x00, 0x01})
+
+	assert.Equal(t, 0, schemaID)
+	assert.Nil(t, payload)
+	assert.EqualError(t, err, "invalid message: expected at least 5 bytes, got 3")
+}